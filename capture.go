@@ -0,0 +1,180 @@
+package cr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrCaptureNotStarted is returned when network capture
+// data is requested before StartNetworkCapture.
+var ErrCaptureNotStarted = errors.New("network capture has not been started")
+
+// NetworkEntry describes a request made by the page and its response.
+type NetworkEntry struct {
+	RequestID network.RequestID
+	URL       string
+	Method    string
+	Type      network.ResourceType
+	Status    int64
+	Headers   network.Headers
+	Timing    *network.ResourceTiming
+	ErrorText string
+
+	Request  *network.Request
+	Response *network.Response
+}
+
+type networkCapture struct {
+	mu      sync.Mutex
+	entries map[network.RequestID]*NetworkEntry
+	order   []network.RequestID
+	cancel  context.CancelFunc
+}
+
+func (c *networkCapture) handle(ev interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch e := ev.(type) {
+	case *network.EventRequestWillBeSent:
+		if _, ok := c.entries[e.RequestID]; !ok {
+			c.order = append(c.order, e.RequestID)
+		}
+		c.entries[e.RequestID] = &NetworkEntry{
+			RequestID: e.RequestID,
+			URL:       e.Request.URL,
+			Method:    e.Request.Method,
+			Type:      e.Type,
+			Request:   e.Request,
+		}
+	case *network.EventResponseReceived:
+		if entry, ok := c.entries[e.RequestID]; ok {
+			entry.Status = e.Response.Status
+			entry.Headers = e.Response.Headers
+			entry.Timing = e.Response.Timing
+			entry.Response = e.Response
+		}
+	case *network.EventLoadingFailed:
+		if entry, ok := c.entries[e.RequestID]; ok {
+			entry.ErrorText = e.ErrorText
+		}
+	}
+}
+
+// StartNetworkCapture begins recording every request made by the page,
+// discarding anything recorded by a previous capture.
+func (b *Browser) StartNetworkCapture() error {
+	b.StopNetworkCapture()
+	ctx, cancel := context.WithCancel(b.ctx)
+	c := &networkCapture{
+		entries: make(map[network.RequestID]*NetworkEntry),
+		cancel:  cancel,
+	}
+	chromedp.ListenTarget(ctx, c.handle)
+	if err := chromedp.Run(b.ctx, network.Enable()); err != nil {
+		cancel()
+		return err
+	}
+	b.capture = c
+	return nil
+}
+
+// StopNetworkCapture stops recording requests. Entries recorded so far
+// remain available through GetNetworkEntries and GetResponseBody.
+func (b *Browser) StopNetworkCapture() {
+	if b.capture != nil {
+		b.capture.cancel()
+	}
+}
+
+// GetNetworkEntries returns the requests recorded by the
+// current or most recent network capture, in the order they were sent.
+func (b *Browser) GetNetworkEntries() ([]*NetworkEntry, error) {
+	c := b.capture
+	if c == nil {
+		return nil, ErrCaptureNotStarted
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := make([]*NetworkEntry, len(c.order))
+	for i, id := range c.order {
+		entries[i] = c.entries[id]
+	}
+	return entries, nil
+}
+
+// GetResponseBody returns the body of a response recorded by the
+// current or most recent network capture. The body must still be held
+// by the browser, so call this before navigating away from the page.
+func (b *Browser) GetResponseBody(requestID network.RequestID) (string, error) {
+	c := b.capture
+	if c == nil {
+		return "", ErrCaptureNotStarted
+	}
+	c.mu.Lock()
+	entry, ok := c.entries[requestID]
+	ok = ok && entry.Response != nil
+	c.mu.Unlock()
+	if !ok {
+		return "", ErrNotFound
+	}
+	var body []byte
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		body, err = network.GetResponseBody(requestID).Do(ctx)
+		return err
+	}))
+	return string(body), err
+}
+
+// WaitForResponse blocks until the page receives a response whose URL
+// matches the glob urlPattern ('*' matches any run of characters, '?' a
+// single one), and returns it. It fails if no such response arrives
+// within timeout.
+func (b *Browser) WaitForResponse(urlPattern string, timeout time.Duration) (*NetworkEntry, error) {
+	re, err := globToRegexp(urlPattern)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, timeout)
+	defer cancel()
+	c := &networkCapture{entries: make(map[network.RequestID]*NetworkEntry)}
+	found := make(chan *NetworkEntry, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		c.handle(ev)
+		e, ok := ev.(*network.EventResponseReceived)
+		if !ok || !re.MatchString(e.Response.URL) {
+			return
+		}
+		c.mu.Lock()
+		entry, ok := c.entries[e.RequestID]
+		c.mu.Unlock()
+		if !ok {
+			// the request was sent before we started listening
+			entry = &NetworkEntry{
+				RequestID: e.RequestID,
+				URL:       e.Response.URL,
+				Type:      e.Type,
+				Status:    e.Response.Status,
+				Headers:   e.Response.Headers,
+				Timing:    e.Response.Timing,
+				Response:  e.Response,
+			}
+		}
+		select {
+		case found <- entry:
+		default:
+		}
+	})
+	select {
+	case entry := <-found:
+		return entry, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for response matching %q: %w", urlPattern, ctx.Err())
+	}
+}
@@ -0,0 +1,232 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ConsoleMessage is a message written to the JavaScript console.
+type ConsoleMessage struct {
+	Type      string
+	Text      string
+	Timestamp time.Time
+}
+
+func (m *ConsoleMessage) String() string {
+	return fmt.Sprintf("[%s] %s", m.Type, m.Text)
+}
+
+// ConsoleErrors lists the console errors found by AssertNoConsoleErrors.
+type ConsoleErrors []*ConsoleMessage
+
+func (e ConsoleErrors) Error() string {
+	texts := make([]string, len(e))
+	for i, m := range e {
+		texts[i] = m.Text
+	}
+	return fmt.Sprintf("%d console error(s): %s", len(e), strings.Join(texts, "; "))
+}
+
+// JSError is an uncaught JavaScript exception thrown by the page.
+type JSError struct {
+	Message   string
+	URL       string
+	Line      int64
+	Column    int64
+	Timestamp time.Time
+}
+
+func (e JSError) Error() string {
+	return fmt.Sprintf("%s (%s:%d:%d)", e.Message, e.URL, e.Line+1, e.Column+1)
+}
+
+func newJSError(e *runtime.EventExceptionThrown) JSError {
+	d := e.ExceptionDetails
+	msg := d.Text
+	if d.Exception != nil && len(d.Exception.Description) > 0 {
+		msg = d.Exception.Description
+	}
+	return JSError{
+		Message:   msg,
+		URL:       d.URL,
+		Line:      d.LineNumber,
+		Column:    d.ColumnNumber,
+		Timestamp: e.Timestamp.Time(),
+	}
+}
+
+// GetPageErrors returns the uncaught JavaScript exceptions thrown
+// since the browser started or ClearPageErrors was last called.
+func (b *Browser) GetPageErrors() ([]JSError, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	errs := make([]JSError, len(b.pageErrors))
+	copy(errs, b.pageErrors)
+	return errs, nil
+}
+
+// ClearPageErrors discards the exceptions returned by GetPageErrors.
+func (b *Browser) ClearPageErrors() {
+	b.mu.Lock()
+	b.pageErrors = nil
+	b.mu.Unlock()
+}
+
+// BrowserLog is an entry of the browser log, such as a network failure,
+// an intervention or a deprecation warning. Unlike console messages
+// these are reported by the browser rather than by page scripts.
+type BrowserLog struct {
+	Level     string
+	Source    string
+	Text      string
+	Timestamp time.Time
+}
+
+func newBrowserLog(e *log.Entry) BrowserLog {
+	return BrowserLog{
+		Level:     e.Level.String(),
+		Source:    e.Source.String(),
+		Text:      e.Text,
+		Timestamp: e.Timestamp.Time(),
+	}
+}
+
+// GetBrowserLogs returns the browser log entries added since the
+// browser started or ClearBrowserLogs was last called.
+func (b *Browser) GetBrowserLogs() ([]BrowserLog, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	logs := make([]BrowserLog, len(b.browserLogs))
+	copy(logs, b.browserLogs)
+	return logs, nil
+}
+
+// ClearBrowserLogs discards the entries returned by GetBrowserLogs.
+func (b *Browser) ClearBrowserLogs() {
+	b.mu.Lock()
+	b.browserLogs = nil
+	b.mu.Unlock()
+}
+
+// remoteObjectText returns a readable representation of a console argument.
+func remoteObjectText(obj *runtime.RemoteObject) string {
+	if len(obj.Value) > 0 {
+		var s string
+		if err := json.Unmarshal(obj.Value, &s); err == nil {
+			return s
+		}
+		return string(obj.Value)
+	}
+	return obj.Description
+}
+
+// StartConsoleCapture begins collecting messages written to the JavaScript
+// console, including uncaught exceptions which are recorded as errors.
+func (b *Browser) StartConsoleCapture() error {
+	b.StopConsoleCapture()
+	ctx, cancel := context.WithCancel(b.ctx)
+	// only errors are kept, so a chatty page cannot crowd them out
+	push := func(m *ConsoleMessage) {
+		if m.Type != runtime.APITypeError.String() {
+			return
+		}
+		b.mu.Lock()
+		b.console = append(b.console, m)
+		b.mu.Unlock()
+	}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *runtime.EventConsoleAPICalled:
+			args := make([]string, len(e.Args))
+			for i, arg := range e.Args {
+				args[i] = remoteObjectText(arg)
+			}
+			push(&ConsoleMessage{
+				Type:      e.Type.String(),
+				Text:      strings.Join(args, " "),
+				Timestamp: e.Timestamp.Time(),
+			})
+		case *runtime.EventExceptionThrown:
+			jsErr := newJSError(e)
+			push(&ConsoleMessage{
+				Type:      runtime.APITypeError.String(),
+				Text:      jsErr.Message,
+				Timestamp: jsErr.Timestamp,
+			})
+		case *page.EventJavascriptDialogOpening:
+			if e.Type != page.DialogTypeAlert {
+				return
+			}
+			b.mu.Lock()
+			b.alerts = append(b.alerts, e.Message)
+			b.mu.Unlock()
+			// an alert blocks the page until it is dismissed
+			go func() {
+				if err := page.HandleJavaScriptDialog(true).Do(b.executor()); err != nil {
+					b.logger.Errorf("Failed to dismiss alert %q: %v", e.Message, err)
+				}
+			}()
+		}
+	})
+	b.mu.Lock()
+	b.console = ConsoleErrors{}
+	b.alerts = nil
+	b.mu.Unlock()
+	if err := chromedp.Run(b.ctx, runtime.Enable()); err != nil {
+		cancel()
+		return err
+	}
+	b.cancelConsole = cancel
+	return nil
+}
+
+// StopConsoleCapture stops collecting console messages.
+func (b *Browser) StopConsoleCapture() {
+	if b.cancelConsole != nil {
+		b.cancelConsole()
+		b.cancelConsole = nil
+	}
+}
+
+// GetAlerts returns the messages of the alert dialogs opened since
+// StartConsoleCapture or ClearAlerts was last called. The alerts
+// are dismissed automatically while console capture is running.
+func (b *Browser) GetAlerts() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	alerts := make([]string, len(b.alerts))
+	copy(alerts, b.alerts)
+	return alerts
+}
+
+// ClearAlerts discards the messages returned by GetAlerts.
+func (b *Browser) ClearAlerts() {
+	b.mu.Lock()
+	b.alerts = nil
+	b.mu.Unlock()
+}
+
+// AssertNoConsoleErrors consumes the error-level messages collected by
+// StartConsoleCapture and returns ConsoleErrors listing them, or nil if
+// there were none.
+func (b *Browser) AssertNoConsoleErrors() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.console == nil {
+		return fmt.Errorf("console capture has not been started")
+	}
+	errs := b.console
+	b.console = ConsoleErrors{}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// GetCookies returns every cookie stored by the browser.
+func (b *Browser) GetCookies() ([]*network.Cookie, error) {
+	var cookies []*network.Cookie
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		cookies, err = network.GetAllCookies().Do(ctx)
+		return err
+	}))
+	return cookies, err
+}
+
+// SetCookies sets all of the given cookies in a single call.
+func (b *Browser) SetCookies(cookies []*network.CookieParam) error {
+	return chromedp.Run(b.ctx, network.SetCookies(cookies))
+}
+
+// ClearCookies deletes every cookie stored by the browser.
+func (b *Browser) ClearCookies() error {
+	return chromedp.Run(b.ctx, network.ClearBrowserCookies())
+}
+
+// ExportCookies writes every cookie stored by the browser to w as a JSON array.
+func (b *Browser) ExportCookies(w io.Writer) error {
+	cookies, err := b.GetCookies()
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(cookies)
+}
+
+// ImportCookies reads cookies written by ExportCookies from r
+// and sets them in the browser.
+func (b *Browser) ImportCookies(r io.Reader) error {
+	var cookies []*network.Cookie
+	if err := json.NewDecoder(r).Decode(&cookies); err != nil {
+		return err
+	}
+	params := make([]*network.CookieParam, len(cookies))
+	for i, c := range cookies {
+		params[i] = cookieParam(c)
+	}
+	return b.SetCookies(params)
+}
+
+// cookieParam converts a stored cookie into the parameters needed to set it.
+func cookieParam(c *network.Cookie) *network.CookieParam {
+	p := &network.CookieParam{
+		Name:         c.Name,
+		Value:        c.Value,
+		Domain:       c.Domain,
+		Path:         c.Path,
+		Secure:       c.Secure,
+		HTTPOnly:     c.HTTPOnly,
+		SameSite:     c.SameSite,
+		Priority:     c.Priority,
+		SameParty:    c.SameParty,
+		SourceScheme: c.SourceScheme,
+		SourcePort:   c.SourcePort,
+	}
+	if !c.Session {
+		sec, frac := math.Modf(c.Expires)
+		expires := cdp.TimeSinceEpoch(time.Unix(int64(sec), int64(frac*float64(time.Second))))
+		p.Expires = &expires
+	}
+	return p
+}
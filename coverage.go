@@ -0,0 +1,79 @@
+package cr
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/css"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/profiler"
+	"github.com/chromedp/chromedp"
+)
+
+// StartCSSCoverage begins tracking which CSS rules are used by the page.
+func (b *Browser) StartCSSCoverage() error {
+	return chromedp.Run(b.ctx,
+		dom.Enable(),
+		css.Enable(),
+		css.StartRuleUsageTracking(),
+	)
+}
+
+// StopCSSCoverage stops CSS rule tracking and returns the
+// usage of every rule seen since StartCSSCoverage.
+func (b *Browser) StopCSSCoverage() ([]*css.RuleUsage, error) {
+	var rules []*css.RuleUsage
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		rules, err = css.StopRuleUsageTracking().Do(ctx)
+		return err
+	}))
+	return rules, err
+}
+
+// CSSCoverageReport summarises rules returned by StopCSSCoverage,
+// returning the number of used and unused rules and the percentage used.
+func CSSCoverageReport(rules []*css.RuleUsage) (used, unused int, pct float64) {
+	for _, rule := range rules {
+		if rule.Used {
+			used++
+		} else {
+			unused++
+		}
+	}
+	if total := used + unused; total > 0 {
+		pct = float64(used) / float64(total) * 100
+	}
+	return
+}
+
+// StartJSCoverage begins collecting JavaScript coverage with call counts.
+// When detailed is true, coverage is reported per block rather than
+// per function.
+func (b *Browser) StartJSCoverage(detailed bool) error {
+	return chromedp.Run(b.ctx,
+		profiler.Enable(),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := profiler.StartPreciseCoverage().
+				WithCallCount(true).
+				WithDetailed(detailed).
+				Do(ctx)
+			return err
+		}),
+	)
+}
+
+// StopJSCoverage returns the coverage collected since StartJSCoverage,
+// including per-function and per-block execution counts, and stops collection.
+func (b *Browser) StopJSCoverage() ([]*profiler.ScriptCoverage, error) {
+	var result []*profiler.ScriptCoverage
+	err := chromedp.Run(b.ctx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			result, _, err = profiler.TakePreciseCoverage().Do(ctx)
+			return err
+		}),
+		profiler.StopPreciseCoverage(),
+		profiler.Disable(),
+	)
+	return result, err
+}
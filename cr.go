@@ -4,12 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/admpub/log"
 	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
 	"github.com/chromedp/chromedp"
 )
 
@@ -21,42 +27,110 @@ const minTimeout = time.Second
 
 // Browser represents a Chrome browser controlled by chromedp.
 type Browser struct {
-	ctx       context.Context
-	cancelCtx context.CancelFunc
-	timeout   time.Duration
-	taskCtx   context.Context
-	logger    *log.Logger
+	ctx           context.Context
+	cancelCtx     context.CancelFunc
+	cancelSession context.CancelFunc
+	timeout       time.Duration
+	taskCtx       context.Context
+	logger        *log.Logger
+
+	cancelConsole    context.CancelFunc
+	cancelRequestLog context.CancelFunc
+	trace            *traceSession
+	capture          *networkCapture
+	webSocket        *webSocketCapture
+	tempDirs         []string
+	afterLaunch      []func(*Browser) error
+
+	mu          sync.Mutex
+	document    *network.Response
+	pageErrors  []JSError
+	alerts      []string
+	console     ConsoleErrors
+	browserLogs []BrowserLog
+	lastNetwork time.Time
+	inflight    map[network.RequestID]struct{}
+	soft        bool
+	lastErr     error
+
+	interception  *fetchDispatcher
+	mediaFeatures map[string]string
+	deviceMetrics *emulation.SetDeviceMetricsOverrideParams
+	frameContexts map[cdp.FrameID]runtime.ExecutionContextID
+	focusedFrame  cdp.FrameID
 }
 
 // New instantiates a new Chrome browser and returns
-// a *Browser used to control it.
+// a *Browser used to control it. The browser is derived from ctx, so it
+// is shut down and its temporary files are removed once ctx is done,
+// e.g. when a test's context expires without Close being called.
 func New(ctx context.Context, args ...chromedp.ExecAllocatorOption) (*Browser, error) {
+	return NewWithOptions(ctx, WithAllocatorOptions(args...))
+}
+
+// NewSoft is like New, but the Must* methods of the returned *Browser
+// record errors for GetLastError instead of ending the program.
+func NewSoft(ctx context.Context, args ...chromedp.ExecAllocatorOption) (*Browser, error) {
+	return NewWithOptions(ctx, WithAllocatorOptions(args...), WithSoftErrors())
+}
+
+// NewWithOptions instantiates a new Chrome browser configured
+// by opts and returns a *Browser used to control it.
+func NewWithOptions(ctx context.Context, opts ...BrowserOption) (*Browser, error) {
 	b := &Browser{
 		timeout: time.Second * 30,
 		logger:  log.GetLogger(`ChromeDP`),
 	}
+	// the browser lifetime is bounded by the default timeout; WithTimeout,
+	// like SetTimeout, only limits waits
 	ctx, cancel := context.WithTimeout(ctx, b.timeout)
+	o := &browserOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.timeout > 0 {
+		b.SetTimeout(o.timeout)
+	}
+	if o.logger != nil {
+		b.logger = o.logger
+	}
+	b.soft = o.soft
+	b.afterLaunch = o.afterLaunch
 	options := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.DisableGPU,
 		chromedp.Headless,
 	)
-	for _, option := range args {
-		options = append(options, option)
-	}
+	options = append(options, o.allocator...)
 
 	allocCtx, _ := chromedp.NewExecAllocator(ctx, options...)
 
 	// also set up a custom logger
 	taskCtx, _ := chromedp.NewContext(allocCtx, chromedp.WithLogf(b.logger.Errorf))
 
+	// cancelling taskCtx would close the browser, so the session
+	// gets a context of its own for StopContext
+	b.ctx, b.cancelSession = context.WithCancel(taskCtx)
+	b.taskCtx = taskCtx
+	b.cancelCtx = cancel
+	b.listen()
+
 	// ensure that the browser process is started
 	if err := chromedp.Run(taskCtx); err != nil {
 		cancel()
 		return b, err
 	}
-	b.ctx = taskCtx
-	b.taskCtx = taskCtx
-	b.cancelCtx = cancel
+
+	for _, fn := range o.afterLaunch {
+		if err := fn(b); err != nil {
+			cancel()
+			return b, err
+		}
+	}
+
+	go func() {
+		<-taskCtx.Done()
+		b.removeTempDirs()
+	}()
 
 	return b, nil
 }
@@ -70,17 +144,119 @@ func (b *Browser) SetTimeout(d time.Duration) {
 	b.timeout = d
 }
 
+// Context switches the *Browser to a new tab, bounded by the browser
+// timeout, and returns its context. As with ResetContext, the state
+// tracked for the previous tab is discarded and tracking follows the
+// new tab.
 func (b *Browser) Context() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithTimeout(b.taskCtx, b.timeout)
-	b.ctx, _ = chromedp.NewContext(ctx)
+	b.resetSession()
+	b.ctx, b.cancelSession = chromedp.NewContext(ctx)
+	b.listen()
 	return b.ctx, cancel
 }
 
+// Done returns a channel that is closed when the browser context
+// is cancelled or expires.
+func (b *Browser) Done() <-chan struct{} {
+	return b.ctx.Done()
+}
+
+// executor returns a context bound to the current target, suitable for
+// issuing CDP commands from inside a chromedp.ListenTarget callback.
+func (b *Browser) executor() context.Context {
+	c := chromedp.FromContext(b.ctx)
+	return cdp.WithExecutor(b.ctx, c.Target)
+}
+
+// fatalf ends the program with the formatted message, or records it
+// as the last error if the *Browser was created with soft errors.
+func (b *Browser) fatalf(format string, args ...interface{}) {
+	if !b.soft {
+		log.Fatalf(format, args...)
+	}
+	err := fmt.Errorf(strings.TrimSuffix(format, "\n"), args...)
+	b.logger.Error(err)
+	b.mu.Lock()
+	b.lastErr = err
+	b.mu.Unlock()
+}
+
+// GetLastError returns the most recent error recorded by a Must*
+// method when soft errors are enabled.
+func (b *Browser) GetLastError() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastErr
+}
+
+// ClearLastError forgets the error returned by GetLastError.
+func (b *Browser) ClearLastError() {
+	b.mu.Lock()
+	b.lastErr = nil
+	b.mu.Unlock()
+}
+
 // Close cleans up the *Browser; this should be called
 // on every *Browser once its work is complete.
 func (b *Browser) Close() error {
+	// remove the files first so the goroutine started by
+	// NewWithOptions finds nothing left to do
+	err := b.removeTempDirs()
 	b.cancelCtx()
-	return nil
+	return err
+}
+
+// removeTempDirs removes the temporary directories created for the
+// *Browser, such as those staging uploads.
+func (b *Browser) removeTempDirs() error {
+	b.mu.Lock()
+	dirs := b.tempDirs
+	b.tempDirs = nil
+	b.mu.Unlock()
+	var err error
+	for _, dir := range dirs {
+		if rerr := os.RemoveAll(dir); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
+
+// AddScriptOnLoad registers a script to be evaluated in every
+// new document before any of the page's own scripts run.
+func (b *Browser) AddScriptOnLoad(script string) (page.ScriptIdentifier, error) {
+	var id page.ScriptIdentifier
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		id, err = page.AddScriptToEvaluateOnNewDocument(script).Do(ctx)
+		return err
+	}))
+	return id, err
+}
+
+// Evaluate evaluates the JavaScript expression in the frame selected by
+// FocusFrame, or the main frame, and decodes the result into res if it
+// is non-nil.
+func (b *Browser) Evaluate(expression string, res interface{}) error {
+	return b.evaluate(expression, res)
+}
+
+func (b *Browser) evaluate(expression string, res interface{}, opts ...chromedp.EvaluateOption) error {
+	return b.evaluateContext(b.ctx, expression, res, opts...)
+}
+
+func (b *Browser) evaluateContext(ctx context.Context, expression string, res interface{}, opts ...chromedp.EvaluateOption) error {
+	id, err := b.frameContext()
+	if err != nil {
+		return err
+	}
+	if id != 0 {
+		opts = append(opts, func(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+			return p.WithContextID(id)
+		})
+	}
+	return chromedp.Run(ctx, chromedp.Evaluate(expression, res, opts...))
 }
 
 // RunAction run single action
@@ -107,7 +283,7 @@ func (b *Browser) Navigate(url string, otherActions ...chromedp.Action) error {
 // MustNavigate calls Navigate and ends execution on error.
 func (b *Browser) MustNavigate(url string, otherActions ...chromedp.Action) {
 	if err := b.Navigate(url, otherActions...); err != nil {
-		log.Fatalf("Failed to navigate to %q: %s\n", url, err)
+		b.fatalf("Failed to navigate to %q: %s\n", url, err)
 	}
 }
 
@@ -118,6 +294,20 @@ func (b *Browser) Location(otherActions ...chromedp.Action) (string, error) {
 	return location, err
 }
 
+// StopPageLoad stops loading the current page, like pressing
+// the browser's stop button.
+func (b *Browser) StopPageLoad() error {
+	return chromedp.Run(b.ctx, page.StopLoading())
+}
+
+// GetPageLoadState returns document.readyState: "loading",
+// "interactive" or "complete".
+func (b *Browser) GetPageLoadState() (string, error) {
+	var state string
+	err := b.evaluate(`document.readyState`, &state)
+	return state, err
+}
+
 // SendKeys sends keystrokes to a DOM element.
 func (b *Browser) SendKeys(xpath, value string) error {
 	return chromedp.Run(b.ctx, chromedp.SendKeys(xpath, value))
@@ -126,7 +316,7 @@ func (b *Browser) SendKeys(xpath, value string) error {
 // MustSendKeys sends keystrokes to a DOM element or halts execution.
 func (b *Browser) MustSendKeys(xpath, value string) {
 	if err := b.SendKeys(xpath, value); err != nil {
-		log.Fatalf("Failed to send %q to %q: %s\n", value, xpath, err)
+		b.fatalf("Failed to send %q to %q: %s\n", value, xpath, err)
 	}
 }
 
@@ -138,7 +328,7 @@ func (b *Browser) Click(xpath string) error {
 // MustClick performs a mouse click or ends the program.
 func (b *Browser) MustClick(xpath string) {
 	if err := b.Click(xpath); err != nil {
-		log.Fatalf("Failed to click %q: %s\n", xpath, err)
+		b.fatalf("Failed to click %q: %s\n", xpath, err)
 	}
 }
 
@@ -149,6 +339,54 @@ func (b *Browser) GetSource() (string, error) {
 	return html, err
 }
 
+// MustGetSource returns the HTML source or ends the program.
+func (b *Browser) MustGetSource() string {
+	html, err := b.GetSource()
+	if err != nil {
+		b.fatalf("Failed to get source: %s\n", err)
+	}
+	return html
+}
+
+// GetText returns the rendered text of a DOM element.
+func (b *Browser) GetText(xpath string) (string, error) {
+	return b.getText(b.ctx, xpath)
+}
+
+// GetElementsText returns the rendered text of every DOM element
+// matching xpath, in document order.
+func (b *Browser) GetElementsText(xpath string) ([]string, error) {
+	nodes, err := b.GetNodes(xpath)
+	if err != nil {
+		return nil, err
+	}
+	texts := make([]string, len(nodes))
+	err = chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for i, node := range nodes {
+			if err := callFunctionOnNode(ctx, node, `function() { return this.innerText; }`, &texts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return texts, err
+}
+
+// MustGetText returns the rendered text of a DOM element or ends the program.
+func (b *Browser) MustGetText(xpath string) string {
+	text, err := b.GetText(xpath)
+	if err != nil {
+		b.fatalf("Failed to get text of %q: %s\n", xpath, err)
+	}
+	return text
+}
+
+func (b *Browser) getText(ctx context.Context, xpath string) (string, error) {
+	var text string
+	err := chromedp.Run(ctx, chromedp.Text(xpath, &text))
+	return text, err
+}
+
 // GetAttributes returns the HTML attributes of a DOM element.
 func (b *Browser) GetAttributes(xpath string) (map[string]string, error) {
 	attrs := make(map[string]string)
@@ -156,6 +394,29 @@ func (b *Browser) GetAttributes(xpath string) (map[string]string, error) {
 	return attrs, err
 }
 
+// MustGetAttributes returns the HTML attributes of a DOM element
+// or ends the program.
+func (b *Browser) MustGetAttributes(xpath string) map[string]string {
+	attrs, err := b.GetAttributes(xpath)
+	if err != nil {
+		b.fatalf("Failed to get attributes of %q: %s\n", xpath, err)
+	}
+	return attrs
+}
+
+// GetNodeAttribute returns the value of the named attribute of a DOM
+// element and whether the attribute is set.
+func (b *Browser) GetNodeAttribute(xpath, name string) (string, bool, error) {
+	return b.getNodeAttribute(b.ctx, xpath, name)
+}
+
+func (b *Browser) getNodeAttribute(ctx context.Context, xpath, name string) (string, bool, error) {
+	var value string
+	var ok bool
+	err := chromedp.Run(ctx, chromedp.AttributeValue(xpath, name, &value, &ok))
+	return value, ok, err
+}
+
 // ClickByXY clicks the browser window in a specific location.
 func (b *Browser) ClickByXY(xpath string) error {
 	x, y, err := b.GetTopLeft(xpath)
@@ -170,6 +431,7 @@ func (b *Browser) GetTopLeft(xpath string) (float64, float64, error) {
 	var top, left float64
 	js := fmt.Sprintf(topLeftJS, xpath)
 	var result string
+	// always the main frame: ClickByXY clicks at page coordinates
 	err := chromedp.Run(b.ctx, chromedp.Evaluate(js, &result))
 	parts := strings.Split(result, ":")
 	if len(parts) == 2 {
@@ -228,6 +490,28 @@ func (b *Browser) GetNodes(xpath string) ([]*cdp.Node, error) {
 	return nodes, err
 }
 
+// CountElements returns the number of DOM elements matching xpath
+// without waiting for any to appear.
+func (b *Browser) CountElements(xpath string) (int, error) {
+	var count int
+	js := `document.evaluate(` + jsString(xpath) + `, document, null, XPathResult.ORDERED_NODE_SNAPSHOT_TYPE, null).snapshotLength`
+	err := b.evaluate(js, &count)
+	return count, err
+}
+
+// AssertElementCount returns an error describing the mismatch unless
+// exactly expected DOM elements match xpath.
+func (b *Browser) AssertElementCount(xpath string, expected int) error {
+	count, err := b.CountElements(xpath)
+	if err != nil {
+		return err
+	}
+	if count != expected {
+		return fmt.Errorf("expected %d element(s) matching %q, found %d", expected, xpath, count)
+	}
+	return nil
+}
+
 var topLeftJS = `
 	function getTopLeft() {
 		var element = document.evaluate("%s",document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null ).singleNodeValue;
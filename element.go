@@ -0,0 +1,96 @@
+package cr
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+)
+
+// Element is a DOM element found by FindAll. Its methods act on the
+// node directly rather than re-running an XPath query.
+type Element struct {
+	b    *Browser
+	Node *cdp.Node
+}
+
+// FindAll returns every DOM element matching xpath.
+func (b *Browser) FindAll(xpath string) ([]*Element, error) {
+	nodes, err := b.GetNodes(xpath)
+	if err != nil {
+		return nil, err
+	}
+	elements := make([]*Element, len(nodes))
+	for i, node := range nodes {
+		elements[i] = &Element{b: b, Node: node}
+	}
+	return elements, nil
+}
+
+func (e *Element) ids() []cdp.NodeID {
+	return []cdp.NodeID{e.Node.NodeID}
+}
+
+func (e *Element) call(fn string, res interface{}, args ...interface{}) error {
+	return chromedp.Run(e.b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		return callFunctionOnNode(ctx, e.Node, fn, res, args...)
+	}))
+}
+
+// Text returns the rendered text of the element.
+func (e *Element) Text() (string, error) {
+	var text string
+	err := e.call(`function() { return this.innerText; }`, &text)
+	return text, err
+}
+
+// Attribute returns the value of the named attribute,
+// or an empty string if it is not set.
+func (e *Element) Attribute(name string) (string, error) {
+	var value string
+	err := e.call(`function(name) { return this.getAttribute(name) || ''; }`, &value, name)
+	return value, err
+}
+
+// GetAttribute returns the value of the named attribute and whether it
+// is set on the element. Failures to read the node are logged and
+// reported as the attribute being absent.
+func (e *Element) GetAttribute(name string) (string, bool) {
+	var value string
+	var ok bool
+	err := chromedp.Run(e.b.ctx, chromedp.AttributeValue(e.ids(), name, &value, &ok, chromedp.ByNodeID))
+	if err != nil {
+		e.b.logger.Errorf("Failed to read attribute %q: %s", name, err)
+		return "", false
+	}
+	return value, ok
+}
+
+// Click performs a mouse click on the element.
+func (e *Element) Click() error {
+	return chromedp.Run(e.b.ctx, chromedp.Click(e.ids(), chromedp.ByNodeID))
+}
+
+// SendKeys sends keystrokes to the element.
+func (e *Element) SendKeys(keys string) error {
+	return chromedp.Run(e.b.ctx, chromedp.SendKeys(e.ids(), keys, chromedp.ByNodeID))
+}
+
+var isVisibleJS = `function() {
+	var style = window.getComputedStyle(this);
+	return style.display !== 'none' &&
+		style.visibility !== 'hidden' &&
+		this.getClientRects().length > 0;
+}`
+
+// IsVisible reports whether the element is rendered on the page.
+func (e *Element) IsVisible() (bool, error) {
+	var visible bool
+	err := e.call(isVisibleJS, &visible)
+	return visible, err
+}
+
+// XPath returns an absolute XPath for the element.
+func (e *Element) XPath() (string, error) {
+	return e.b.GetXPathForNode(e.Node)
+}
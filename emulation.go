@@ -0,0 +1,140 @@
+package cr
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrInvalidLocale is returned by SetLocale for a malformed language tag.
+var ErrInvalidLocale = errors.New("invalid BCP 47 language tag")
+
+// localeRegexp matches BCP 47 language tags made up of a language,
+// optional script and region, and any number of variants,
+// e.g. "en", "en-US", "zh-Hant-TW".
+var localeRegexp = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z]{4})?(-([a-zA-Z]{2}|[0-9]{3}))?(-([a-zA-Z0-9]{5,8}|[0-9][a-zA-Z0-9]{3}))*$`)
+
+// setDeviceMetrics applies a device metrics override and remembers it, so
+// later changes to a single metric can resend the others unchanged.
+func (b *Browser) setDeviceMetrics(p *emulation.SetDeviceMetricsOverrideParams) error {
+	if err := chromedp.Run(b.ctx, p); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.deviceMetrics = p
+	b.mu.Unlock()
+	return nil
+}
+
+// emulateViewport overrides the viewport size like chromedp.EmulateViewport,
+// keeping track of the override for SetDeviceScaleFactor.
+func (b *Browser) emulateViewport(width, height int64) error {
+	if err := b.setDeviceMetrics(emulation.SetDeviceMetricsOverride(width, height, 1, false)); err != nil {
+		return err
+	}
+	return chromedp.Run(b.ctx, emulation.SetTouchEmulationEnabled(false))
+}
+
+// SetDeviceScaleFactor renders the page at the given pixel density,
+// e.g. 2 for Retina screenshots. A viewport size or mobile emulation set
+// through this package is kept; overrides applied directly with chromedp,
+// such as chromedp.EmulateViewport run through RunTasks, are not known
+// and are replaced.
+func (b *Browser) SetDeviceScaleFactor(factor float64) error {
+	p := emulation.SetDeviceMetricsOverride(0, 0, factor, false)
+	b.mu.Lock()
+	if b.deviceMetrics != nil {
+		current := *b.deviceMetrics
+		current.DeviceScaleFactor = factor
+		p = &current
+	}
+	b.mu.Unlock()
+	return b.setDeviceMetrics(p)
+}
+
+// SetDevicePixelRatio sets window.devicePixelRatio, which drives
+// resolution media queries, srcset selection and canvas backing store
+// sizes. It is the same device scale factor as SetDeviceScaleFactor,
+// named after the value the page observes.
+func (b *Browser) SetDevicePixelRatio(ratio float64) error {
+	return b.SetDeviceScaleFactor(ratio)
+}
+
+// SetLocale overrides the locale reported to the page, such as the
+// value of navigator.language and the default for Intl formatting.
+func (b *Browser) SetLocale(locale string) error {
+	if !localeRegexp.MatchString(locale) {
+		return fmt.Errorf("%w: %q", ErrInvalidLocale, locale)
+	}
+	return chromedp.Run(b.ctx, emulation.SetLocaleOverride().WithLocale(locale))
+}
+
+// SetTimezone overrides the timezone of the page with an IANA
+// timezone ID such as "Europe/Berlin".
+func (b *Browser) SetTimezone(timezoneID string) error {
+	return chromedp.Run(b.ctx, emulation.SetTimezoneOverride(timezoneID))
+}
+
+// SetGeolocation overrides the position reported by the Geolocation API.
+func (b *Browser) SetGeolocation(latitude, longitude, accuracy float64) error {
+	return chromedp.Run(b.ctx, emulation.SetGeolocationOverride().
+		WithLatitude(latitude).
+		WithLongitude(longitude).
+		WithAccuracy(accuracy))
+}
+
+// SetCPUThrottling slows down the CPU by the given factor; 4 roughly
+// simulates a mid-range mobile device.
+func (b *Browser) SetCPUThrottling(rate float64) error {
+	return chromedp.Run(b.ctx, emulation.SetCPUThrottlingRate(rate))
+}
+
+// ClearCPUThrottling removes CPU throttling set by SetCPUThrottling.
+func (b *Browser) ClearCPUThrottling() error {
+	return b.SetCPUThrottling(1)
+}
+
+// setMediaFeature emulates a CSS media feature. Chrome replaces every
+// emulated feature on each call, so the features set so far are kept
+// and sent together.
+func (b *Browser) setMediaFeature(name, value string) error {
+	b.mu.Lock()
+	if b.mediaFeatures == nil {
+		b.mediaFeatures = make(map[string]string)
+	}
+	b.mediaFeatures[name] = value
+	features := make([]*emulation.MediaFeature, 0, len(b.mediaFeatures))
+	for name, value := range b.mediaFeatures {
+		features = append(features, &emulation.MediaFeature{Name: name, Value: value})
+	}
+	b.mu.Unlock()
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Name < features[j].Name
+	})
+	return chromedp.Run(b.ctx, emulation.SetEmulatedMedia().WithFeatures(features))
+}
+
+// EmulateReducedMotion makes the page match the
+// prefers-reduced-motion: reduce media query when prefer is true,
+// and prefers-reduced-motion: no-preference otherwise.
+func (b *Browser) EmulateReducedMotion(prefer bool) error {
+	value := `no-preference`
+	if prefer {
+		value = `reduce`
+	}
+	return b.setMediaFeature(`prefers-reduced-motion`, value)
+}
+
+// EmulateHighContrast makes the page match the forced-colors: active
+// media query when prefer is true, and forced-colors: none otherwise.
+func (b *Browser) EmulateHighContrast(prefer bool) error {
+	value := `none`
+	if prefer {
+		value = `active`
+	}
+	return b.setMediaFeature(`forced-colors`, value)
+}
@@ -0,0 +1,91 @@
+package cr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// GetFrames returns the main frame followed by every nested frame on the page.
+func (b *Browser) GetFrames() ([]*cdp.Frame, error) {
+	var tree *page.FrameTree
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		tree, err = page.GetFrameTree().Do(ctx)
+		return err
+	}))
+	if err != nil {
+		return nil, err
+	}
+	var frames []*cdp.Frame
+	var walk func(*page.FrameTree)
+	walk = func(t *page.FrameTree) {
+		frames = append(frames, t.Frame)
+		for _, child := range t.ChildFrames {
+			walk(child)
+		}
+	}
+	walk(tree)
+	return frames, nil
+}
+
+// GetAllFrameURLs returns the URL of the main frame followed by
+// those of every nested frame on the page.
+func (b *Browser) GetAllFrameURLs() ([]string, error) {
+	frames, err := b.GetFrames()
+	if err != nil {
+		return nil, err
+	}
+	urls := make([]string, len(frames))
+	for i, frame := range frames {
+		urls[i] = frame.URL
+	}
+	return urls, nil
+}
+
+// ErrNoFrameContext is returned when JavaScript is to run in a focused
+// frame that currently has no execution context, e.g. because it has
+// been removed or is an out-of-process frame.
+var ErrNoFrameContext = errors.New("no execution context for frame")
+
+// FocusFrame makes Evaluate, and the helpers built on it, run JavaScript
+// in the frame frameID as returned by GetFrames. Scripts run in the
+// frame's own context, so they see its globals, and the focus follows
+// the frame across navigations.
+func (b *Browser) FocusFrame(frameID cdp.FrameID) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.frameContexts[frameID]; !ok {
+		return fmt.Errorf("%w %s", ErrNoFrameContext, frameID)
+	}
+	b.focusedFrame = frameID
+	return nil
+}
+
+// FocusMainFrame makes Evaluate run JavaScript in the main frame again.
+func (b *Browser) FocusMainFrame() error {
+	b.mu.Lock()
+	b.focusedFrame = ""
+	b.mu.Unlock()
+	return nil
+}
+
+// frameContext returns the execution context of the focused frame,
+// or zero for the main frame.
+func (b *Browser) frameContext() (runtime.ExecutionContextID, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.focusedFrame) == 0 {
+		return 0, nil
+	}
+	id, ok := b.frameContexts[b.focusedFrame]
+	if !ok {
+		return 0, fmt.Errorf("%w %s", ErrNoFrameContext, b.focusedFrame)
+	}
+	return id, nil
+}
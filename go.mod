@@ -6,6 +6,7 @@ require (
 	github.com/admpub/log v0.3.1
 	github.com/chromedp/cdproto v0.0.0-20210526005521-9e51b9051fd0
 	github.com/chromedp/chromedp v0.7.3
+	github.com/mailru/easyjson v0.7.7
 	github.com/mattn/go-isatty v0.0.13 // indirect
 	golang.org/x/sys v0.0.0-20210531225629-47163c9f4e4f // indirect
 )
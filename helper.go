@@ -2,13 +2,38 @@ package cr
 
 import (
 	"context"
+	"encoding/json"
 	"math"
+	"regexp"
+	"strings"
 
 	"github.com/chromedp/cdproto/emulation"
 	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/cdproto/runtime"
 	cdp "github.com/chromedp/chromedp"
 )
 
+// globToRegexp compiles a glob pattern, in which '*' matches any run of
+// characters and '?' a single character, into an anchored regexp.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	expr := regexp.QuoteMeta(pattern)
+	expr = strings.ReplaceAll(expr, `\*`, `.*`)
+	expr = strings.ReplaceAll(expr, `\?`, `.`)
+	return regexp.Compile(`^` + expr + `$`)
+}
+
+// jsString returns s quoted as a JavaScript string literal.
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+// awaitPromise is an evaluate option that waits for a returned
+// Promise to settle and uses its result.
+func awaitPromise(p *runtime.EvaluateParams) *runtime.EvaluateParams {
+	return p.WithAwaitPromise(true)
+}
+
 // fullScreenshot takes a screenshot of the entire browser viewport.
 //
 // Liberally copied from puppeteer's source.
@@ -0,0 +1,96 @@
+package cr
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/chromedp/cdproto/input"
+	"github.com/chromedp/chromedp"
+)
+
+// MouseMoveSmooth moves the mouse from (fromX, fromY) to (toX, toY) in
+// steps mousemove events along a randomly curved cubic Bézier path,
+// which looks more natural than a straight line.
+func (b *Browser) MouseMoveSmooth(fromX, fromY, toX, toY float64, steps int) error {
+	if steps < 1 {
+		steps = 1
+	}
+	dx, dy := toX-fromX, toY-fromY
+	// control points are offset from the straight line by up to a
+	// third of the distance on each axis
+	c1x := fromX + dx/3 + (rand.Float64()-0.5)*dy*2/3
+	c1y := fromY + dy/3 + (rand.Float64()-0.5)*dx*2/3
+	c2x := fromX + dx*2/3 + (rand.Float64()-0.5)*dy*2/3
+	c2y := fromY + dy*2/3 + (rand.Float64()-0.5)*dx*2/3
+
+	return chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		for i := 1; i <= steps; i++ {
+			t := float64(i) / float64(steps)
+			x := bezier(t, fromX, c1x, c2x, toX)
+			y := bezier(t, fromY, c1y, c2y, toY)
+			if err := input.DispatchMouseEvent(input.MouseMoved, x, y).Do(ctx); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+}
+
+// bezier evaluates a cubic Bézier curve with control values p0..p3 at t.
+func bezier(t, p0, p1, p2, p3 float64) float64 {
+	u := 1 - t
+	return u*u*u*p0 + 3*u*u*t*p1 + 3*u*t*t*p2 + t*t*t*p3
+}
+
+// jitter returns d randomly adjusted by up to half its length either way.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)+1))
+}
+
+// TypeWithDelay sends text to a DOM element one character at a time,
+// pausing for roughly delay, with random jitter, between keystrokes.
+func (b *Browser) TypeWithDelay(xpath, text string, delay time.Duration) error {
+	for i, r := range []rune(text) {
+		if i > 0 {
+			time.Sleep(jitter(delay))
+		}
+		if err := chromedp.Run(b.ctx, chromedp.SendKeys(xpath, string(r))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gaussianJitter returns d varied by normally distributed noise with a
+// standard deviation of a third of d, never less than a tenth of d.
+func gaussianJitter(d time.Duration) time.Duration {
+	j := time.Duration(float64(d) * (1 + rand.NormFloat64()/3))
+	if floor := d / 10; j < floor {
+		return floor
+	}
+	return j
+}
+
+// SimulateTyping sends text to a DOM element one character at a time at
+// roughly wpm words per minute, taking a word as five characters, with
+// Gaussian jitter between keystrokes.
+func (b *Browser) SimulateTyping(xpath, text string, wpm int) error {
+	if wpm <= 0 {
+		return fmt.Errorf("invalid typing speed: %d wpm", wpm)
+	}
+	delay := time.Minute / time.Duration(wpm*5)
+	for i, r := range []rune(text) {
+		if i > 0 {
+			time.Sleep(gaussianJitter(delay))
+		}
+		if err := chromedp.Run(b.ctx, chromedp.SendKeys(xpath, string(r))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
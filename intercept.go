@@ -0,0 +1,316 @@
+package cr
+
+import (
+	"context"
+	"encoding/base64"
+	"regexp"
+	"sync"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// fetchDispatcher owns the Fetch domain of a tab. Chrome keeps a single
+// Fetch configuration per tab and every paused request must be resolved
+// exactly once, so Use, OnResponse, SetHTTPAuth and StubResponse register
+// with the dispatcher instead of enabling Fetch themselves.
+type fetchDispatcher struct {
+	mu         sync.Mutex
+	middleware []RequestMiddleware
+	stubs      []*responseStub
+	handlers   []*responseHandler
+	auth       *fetch.AuthChallengeResponse
+
+	// authAttempts holds the requests already given credentials, so a
+	// rejected challenge is cancelled instead of answered forever
+	authAttempts map[fetch.RequestID]bool
+
+	// cancel removes the listener; it is non-nil while Fetch is enabled
+	cancel context.CancelFunc
+}
+
+type responseStub struct {
+	pattern *regexp.Regexp
+	status  int64
+	headers []*fetch.HeaderEntry
+	body    string
+}
+
+func (s *responseStub) matches(e *fetch.EventRequestPaused) bool {
+	if e.ResourceType != network.ResourceTypeXHR && e.ResourceType != network.ResourceTypeFetch {
+		return false
+	}
+	return s.pattern.MatchString(e.Request.URL)
+}
+
+type responseHandler struct {
+	fn func(*network.Response) []byte
+}
+
+// interceptor returns the Fetch dispatcher of the current session.
+func (b *Browser) interceptor() *fetchDispatcher {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.interception == nil {
+		b.interception = &fetchDispatcher{}
+	}
+	return b.interception
+}
+
+// updateFetch applies change to the dispatcher and reconfigures the Fetch
+// domain to match. If that fails, undo is called to roll the change back.
+func (b *Browser) updateFetch(change func(d *fetchDispatcher), undo func(d *fetchDispatcher)) error {
+	d := b.interceptor()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	change(d)
+	if err := b.syncFetch(d); err != nil {
+		undo(d)
+		return err
+	}
+	return nil
+}
+
+// syncFetch enables, reconfigures or disables the Fetch domain according
+// to what is registered with d. d.mu must be held.
+func (b *Browser) syncFetch(d *fetchDispatcher) error {
+	active := len(d.middleware) > 0 || len(d.stubs) > 0 || len(d.handlers) > 0 || d.auth != nil
+	if !active {
+		if d.cancel == nil {
+			return nil
+		}
+		d.cancel()
+		d.cancel = nil
+		return chromedp.Run(b.ctx, fetch.Disable())
+	}
+	patterns := []*fetch.RequestPattern{
+		{URLPattern: `*`, RequestStage: fetch.RequestStageRequest},
+	}
+	if len(d.handlers) > 0 {
+		patterns = append(patterns, &fetch.RequestPattern{URLPattern: `*`, RequestStage: fetch.RequestStageResponse})
+	}
+	enable := fetch.Enable().WithPatterns(patterns).WithHandleAuthRequests(d.auth != nil)
+	if d.cancel != nil {
+		return chromedp.Run(b.ctx, enable)
+	}
+	ctx, cancel := context.WithCancel(b.ctx)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		// listeners run on chromedp's event loop, which must not block
+		switch e := ev.(type) {
+		case *fetch.EventRequestPaused:
+			go b.resolvePaused(d, e)
+		case *fetch.EventAuthRequired:
+			go b.answerAuth(d, e)
+		}
+	})
+	if err := chromedp.Run(b.ctx, enable); err != nil {
+		cancel()
+		return err
+	}
+	d.cancel = cancel
+	return nil
+}
+
+// resolvePaused resolves a paused request: stubs are answered first, then
+// requests go through the middleware chain and responses through the
+// response handlers.
+func (b *Browser) resolvePaused(d *fetchDispatcher, e *fetch.EventRequestPaused) {
+	d.mu.Lock()
+	chain, stubs, handlers := d.middleware, d.stubs, d.handlers
+	d.mu.Unlock()
+
+	ctx := b.executor()
+	if e.ResponseStatusCode == 0 && len(e.ResponseErrorReason) == 0 {
+		// request phase; the most recently added stub wins
+		for i := len(stubs) - 1; i >= 0; i-- {
+			s := stubs[i]
+			if !s.matches(e) {
+				continue
+			}
+			err := fetch.FulfillRequest(e.RequestID, s.status).
+				WithResponseHeaders(s.headers).
+				WithBody(s.body).
+				Do(ctx)
+			if err != nil {
+				b.logger.Errorf("Failed to stub response for %s: %v", e.Request.URL, err)
+			}
+			return
+		}
+		b.runMiddleware(ctx, chain, e)
+		return
+	}
+	for _, h := range handlers {
+		body := b.callResponseHandler(h.fn, e)
+		if body == nil {
+			continue
+		}
+		err := fetch.FulfillRequest(e.RequestID, e.ResponseStatusCode).
+			WithResponseHeaders(e.ResponseHeaders).
+			WithBody(base64.StdEncoding.EncodeToString(body)).
+			Do(ctx)
+		if err != nil {
+			b.logger.Errorf("Failed to fulfill response %s: %v", e.Request.URL, err)
+		}
+		return
+	}
+	if err := fetch.ContinueRequest(e.RequestID).Do(ctx); err != nil {
+		b.logger.Errorf("Failed to continue response %s: %v", e.Request.URL, err)
+	}
+}
+
+// answerAuth answers an authentication challenge with the credentials set
+// by SetHTTPAuth, or lets the browser handle it if there are none. A
+// repeated challenge for the same request means the credentials were
+// rejected, so it is cancelled.
+func (b *Browser) answerAuth(d *fetchDispatcher, e *fetch.EventAuthRequired) {
+	d.mu.Lock()
+	auth := d.auth
+	if auth != nil {
+		if d.authAttempts[e.RequestID] {
+			auth = &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseCancelAuth}
+		} else {
+			if d.authAttempts == nil {
+				d.authAttempts = make(map[fetch.RequestID]bool)
+			}
+			d.authAttempts[e.RequestID] = true
+		}
+	}
+	d.mu.Unlock()
+	if auth == nil {
+		auth = &fetch.AuthChallengeResponse{Response: fetch.AuthChallengeResponseResponseDefault}
+	}
+	if err := fetch.ContinueWithAuth(e.RequestID, auth).Do(b.executor()); err != nil {
+		b.logger.Errorf("Failed to answer auth challenge for %s: %v", e.Request.URL, err)
+	}
+}
+
+// OnResponse intercepts responses before they reach the page. fn receives
+// the response metadata and may return a replacement body; returning nil
+// passes the original response through unmodified. Call remove to stop
+// intercepting.
+func (b *Browser) OnResponse(fn func(*network.Response) []byte) (remove func(), err error) {
+	h := &responseHandler{fn: fn}
+	err = b.updateFetch(func(d *fetchDispatcher) {
+		d.handlers = append(d.handlers[:len(d.handlers):len(d.handlers)], h)
+	}, func(d *fetchDispatcher) {
+		d.handlers = removeResponseHandler(d.handlers, h)
+	})
+	if err != nil {
+		return nil, err
+	}
+	remove = func() {
+		err := b.updateFetch(func(d *fetchDispatcher) {
+			d.handlers = removeResponseHandler(d.handlers, h)
+		}, func(d *fetchDispatcher) {})
+		if err != nil {
+			b.logger.Errorf("Failed to update fetch interception: %v", err)
+		}
+	}
+	return remove, nil
+}
+
+func removeResponseHandler(handlers []*responseHandler, h *responseHandler) []*responseHandler {
+	kept := make([]*responseHandler, 0, len(handlers))
+	for _, v := range handlers {
+		if v != h {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// callResponseHandler invokes fn, recovering from and logging any panic
+// so that a faulty handler does not take down the listener.
+func (b *Browser) callResponseHandler(fn func(*network.Response) []byte, e *fetch.EventRequestPaused) (body []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Errorf("Response handler for %s panicked: %v", e.Request.URL, r)
+			body = nil
+		}
+	}()
+	headers := network.Headers{}
+	for _, h := range e.ResponseHeaders {
+		headers[h.Name] = h.Value
+	}
+	return fn(&network.Response{
+		URL:     e.Request.URL,
+		Status:  e.ResponseStatusCode,
+		Headers: headers,
+	})
+}
+
+// SetHTTPAuth answers HTTP authentication challenges on subsequent
+// navigations with the given credentials until ClearHTTPAuth is called.
+func (b *Browser) SetHTTPAuth(username, password string) error {
+	auth := &fetch.AuthChallengeResponse{
+		Response: fetch.AuthChallengeResponseResponseProvideCredentials,
+		Username: username,
+		Password: password,
+	}
+	var prev *fetch.AuthChallengeResponse
+	return b.updateFetch(func(d *fetchDispatcher) {
+		prev, d.auth = d.auth, auth
+		// new credentials deserve a fresh attempt
+		d.authAttempts = nil
+	}, func(d *fetchDispatcher) {
+		d.auth = prev
+	})
+}
+
+// ClearHTTPAuth removes credentials installed by SetHTTPAuth.
+func (b *Browser) ClearHTTPAuth() error {
+	var prev *fetch.AuthChallengeResponse
+	return b.updateFetch(func(d *fetchDispatcher) {
+		prev, d.auth = d.auth, nil
+	}, func(d *fetchDispatcher) {
+		d.auth = prev
+	})
+}
+
+// StubResponse answers fetch and XHR requests whose URL matches the glob
+// urlPattern ('*' matches any run of characters, '?' a single one) with
+// the given status, body and headers, without contacting the server.
+// Call remove to stop stubbing.
+func (b *Browser) StubResponse(urlPattern string, statusCode int, body []byte, headers map[string]string) (remove func(), err error) {
+	pattern, err := globToRegexp(urlPattern)
+	if err != nil {
+		return nil, err
+	}
+	s := &responseStub{
+		pattern: pattern,
+		status:  int64(statusCode),
+		headers: make([]*fetch.HeaderEntry, 0, len(headers)),
+		body:    base64.StdEncoding.EncodeToString(body),
+	}
+	for name, value := range headers {
+		s.headers = append(s.headers, &fetch.HeaderEntry{Name: name, Value: value})
+	}
+	err = b.updateFetch(func(d *fetchDispatcher) {
+		d.stubs = append(d.stubs[:len(d.stubs):len(d.stubs)], s)
+	}, func(d *fetchDispatcher) {
+		d.stubs = removeResponseStub(d.stubs, s)
+	})
+	if err != nil {
+		return nil, err
+	}
+	remove = func() {
+		err := b.updateFetch(func(d *fetchDispatcher) {
+			d.stubs = removeResponseStub(d.stubs, s)
+		}, func(d *fetchDispatcher) {})
+		if err != nil {
+			b.logger.Errorf("Failed to update fetch interception: %v", err)
+		}
+	}
+	return remove, nil
+}
+
+func removeResponseStub(stubs []*responseStub, s *responseStub) []*responseStub {
+	kept := make([]*responseStub, 0, len(stubs))
+	for _, v := range stubs {
+		if v != s {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,109 @@
+package cr
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/chromedp/cdproto/fetch"
+	"github.com/chromedp/cdproto/network"
+)
+
+// Next passes control to the next middleware in the chain. Once the
+// chain is exhausted it lets the request proceed.
+type Next func() error
+
+// RequestMiddleware intercepts a request made by the page. It may modify
+// the URL, Method, Headers and PostData of req before calling next.
+// Returning without calling next blocks the request, and returning an
+// error fails it.
+type RequestMiddleware func(req *network.Request, next Next) error
+
+// Use appends middleware to the chain run for every request the page
+// makes. If interception cannot be enabled the chain is left unchanged.
+func (b *Browser) Use(middleware ...RequestMiddleware) error {
+	var prev []RequestMiddleware
+	return b.updateFetch(func(d *fetchDispatcher) {
+		prev = d.middleware
+		// never share the backing array with a chain being run
+		d.middleware = append(d.middleware[:len(d.middleware):len(d.middleware)], middleware...)
+	}, func(d *fetchDispatcher) {
+		d.middleware = prev
+	})
+}
+
+// runMiddleware passes a paused request through chain and resolves it.
+// With an empty chain the request simply continues.
+func (b *Browser) runMiddleware(ctx context.Context, chain []RequestMiddleware, e *fetch.EventRequestPaused) {
+	req := e.Request
+	orig := *req
+	origHeaders := make(network.Headers, len(req.Headers))
+	for k, v := range req.Headers {
+		origHeaders[k] = v
+	}
+
+	var passed bool
+	var next func(i int) error
+	next = func(i int) error {
+		if i == len(chain) {
+			passed = true
+			return nil
+		}
+		return chain[i](req, func() error { return next(i + 1) })
+	}
+
+	err := next(0)
+	if err != nil {
+		b.logger.Errorf("Request middleware failed for %s: %v", orig.URL, err)
+		err = fetch.FailRequest(e.RequestID, network.ErrorReasonFailed).Do(ctx)
+	} else if !passed {
+		err = fetch.FailRequest(e.RequestID, network.ErrorReasonBlockedByClient).Do(ctx)
+	} else {
+		p := fetch.ContinueRequest(e.RequestID)
+		if req.URL != orig.URL {
+			p = p.WithURL(req.URL)
+		}
+		if req.Method != orig.Method {
+			p = p.WithMethod(req.Method)
+		}
+		if req.PostData != orig.PostData {
+			p = p.WithPostData(base64.StdEncoding.EncodeToString([]byte(req.PostData)))
+		}
+		if !reflect.DeepEqual(req.Headers, origHeaders) {
+			headers := make([]*fetch.HeaderEntry, 0, len(req.Headers))
+			for name, value := range req.Headers {
+				headers = append(headers, &fetch.HeaderEntry{Name: name, Value: fmt.Sprint(value)})
+			}
+			p = p.WithHeaders(headers)
+		}
+		err = p.Do(ctx)
+	}
+	if err != nil {
+		b.logger.Errorf("Failed to resolve request %s: %v", orig.URL, err)
+	}
+}
+
+// SetHeadersForDomain adds headers to every request sent to domain or
+// one of its subdomains. Unlike network.SetExtraHTTPHeaders, requests to
+// other hosts are left untouched.
+func (b *Browser) SetHeadersForDomain(domain string, headers map[string]string) error {
+	return b.Use(func(req *network.Request, next Next) error {
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return err
+		}
+		host := u.Hostname()
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			if req.Headers == nil {
+				req.Headers = make(network.Headers, len(headers))
+			}
+			for name, value := range headers {
+				req.Headers[name] = value
+			}
+		}
+		return next()
+	})
+}
@@ -0,0 +1,225 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/log"
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrNoDocument is returned when no top-level document
+// response has been received yet.
+var ErrNoDocument = errors.New("no document response received")
+
+// ErrUnexpectedStatus is returned by NavigateExpecting when the
+// page responds with a different HTTP status code.
+var ErrUnexpectedStatus = errors.New("unexpected HTTP status")
+
+// listen registers the listeners that track the state of the session's
+// tab. It may be called before the tab's context first runs, so that the
+// events sent while Chrome attaches to the tab are seen too.
+func (b *Browser) listen() {
+	c := chromedp.FromContext(b.ctx)
+	b.mu.Lock()
+	b.lastNetwork = time.Now()
+	b.inflight = make(map[network.RequestID]struct{})
+	b.frameContexts = make(map[cdp.FrameID]runtime.ExecutionContextID)
+	b.mu.Unlock()
+	chromedp.ListenTarget(b.ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *network.EventResponseReceived:
+			// Chrome gives the main frame the same ID as its target
+			if e.Type != network.ResourceTypeDocument || e.FrameID != cdp.FrameID(c.Target.TargetID) {
+				return
+			}
+			b.mu.Lock()
+			b.document = e.Response
+			b.mu.Unlock()
+		case *network.EventRequestWillBeSent:
+			// a redirect reuses the request ID of the original request
+			if e.RedirectResponse != nil {
+				return
+			}
+			b.mu.Lock()
+			b.inflight[e.RequestID] = struct{}{}
+			b.mu.Unlock()
+		case *network.EventLoadingFinished:
+			b.requestDone(e.RequestID)
+		case *network.EventLoadingFailed:
+			b.requestDone(e.RequestID)
+		case *runtime.EventExceptionThrown:
+			b.mu.Lock()
+			b.pageErrors = append(b.pageErrors, newJSError(e))
+			b.mu.Unlock()
+		case *runtime.EventExecutionContextCreated:
+			var aux struct {
+				FrameID   cdp.FrameID `json:"frameId"`
+				IsDefault bool        `json:"isDefault"`
+			}
+			if err := json.Unmarshal(e.Context.AuxData, &aux); err != nil || !aux.IsDefault {
+				return
+			}
+			b.mu.Lock()
+			b.frameContexts[aux.FrameID] = e.Context.ID
+			b.mu.Unlock()
+		case *runtime.EventExecutionContextDestroyed:
+			b.mu.Lock()
+			for frameID, id := range b.frameContexts {
+				if id == e.ExecutionContextID {
+					delete(b.frameContexts, frameID)
+				}
+			}
+			b.mu.Unlock()
+		case *runtime.EventExecutionContextsCleared:
+			b.mu.Lock()
+			b.frameContexts = make(map[cdp.FrameID]runtime.ExecutionContextID)
+			b.mu.Unlock()
+		case *log.EventEntryAdded:
+			b.mu.Lock()
+			b.browserLogs = append(b.browserLogs, newBrowserLog(e.Entry))
+			b.mu.Unlock()
+		}
+	})
+}
+
+// requestDone records the end of a request for GetNetworkIdleTime.
+func (b *Browser) requestDone(id network.RequestID) {
+	b.mu.Lock()
+	delete(b.inflight, id)
+	b.lastNetwork = time.Now()
+	b.mu.Unlock()
+}
+
+// documentResponse returns the response of the last top-level navigation.
+func (b *Browser) documentResponse() (*network.Response, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.document == nil {
+		return nil, ErrNoDocument
+	}
+	return b.document, nil
+}
+
+// GetDocumentResponseHeaders returns the HTTP headers of the response
+// to the last top-level navigation.
+func (b *Browser) GetDocumentResponseHeaders() (map[string]string, error) {
+	resp, err := b.documentResponse()
+	if err != nil {
+		return nil, err
+	}
+	headers := make(map[string]string, len(resp.Headers))
+	for name, value := range resp.Headers {
+		if s, ok := value.(string); ok {
+			headers[name] = s
+		}
+	}
+	return headers, nil
+}
+
+// GetHTTPStatusCode returns the HTTP status code of the response
+// to the last top-level navigation.
+func (b *Browser) GetHTTPStatusCode() (int, error) {
+	resp, err := b.documentResponse()
+	if err != nil {
+		return 0, err
+	}
+	return int(resp.Status), nil
+}
+
+// GetNetworkIdleTime returns how long it has been since the last
+// network request finished or failed, or zero while any request
+// is still in flight.
+func (b *Browser) GetNetworkIdleTime() (time.Duration, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.inflight) > 0 {
+		return 0, nil
+	}
+	return time.Since(b.lastNetwork), nil
+}
+
+// NavigateExpecting navigates to urlStr and returns ErrUnexpectedStatus
+// if the document is not served with expectedStatus.
+func (b *Browser) NavigateExpecting(urlStr string, expectedStatus int) error {
+	resp, err := chromedp.RunResponse(b.ctx, chromedp.Navigate(urlStr))
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return ErrNoDocument
+	}
+	if int(resp.Status) != expectedStatus {
+		return fmt.Errorf("%w: got %d, want %d for %s", ErrUnexpectedStatus, resp.Status, expectedStatus, urlStr)
+	}
+	return nil
+}
+
+// GetResponseStatusForURL loads urlStr in a temporary tab and returns the
+// HTTP status code of the response, leaving the current page untouched.
+func (b *Browser) GetResponseStatusForURL(urlStr string) (int, error) {
+	tab, err := b.Clone()
+	if err != nil {
+		return 0, err
+	}
+	defer tab.Close()
+	ctx, cancel := context.WithTimeout(tab.ctx, b.timeout)
+	defer cancel()
+	resp, err := chromedp.RunResponse(ctx, chromedp.Navigate(urlStr))
+	if err != nil {
+		return 0, err
+	}
+	if resp == nil {
+		return 0, ErrNoDocument
+	}
+	return int(resp.Status), nil
+}
+
+// EnableRequestLogging logs every request sent by the page at debug
+// level until DisableRequestLogging is called.
+func (b *Browser) EnableRequestLogging() error {
+	b.DisableRequestLogging()
+	ctx, cancel := context.WithCancel(b.ctx)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if e, ok := ev.(*network.EventRequestWillBeSent); ok {
+			b.logger.Debugf("Request: %s %s (%s)", e.Request.Method, e.Request.URL, e.Type)
+		}
+	})
+	if err := chromedp.Run(b.ctx, network.Enable()); err != nil {
+		cancel()
+		return err
+	}
+	b.cancelRequestLog = cancel
+	return nil
+}
+
+// DisableRequestLogging stops the logging started by EnableRequestLogging.
+func (b *Browser) DisableRequestLogging() error {
+	if b.cancelRequestLog != nil {
+		b.cancelRequestLog()
+		b.cancelRequestLog = nil
+	}
+	return nil
+}
+
+// SetOffline toggles emulation of a complete network disconnection.
+func (b *Browser) SetOffline(offline bool) error {
+	// -1 disables throttling when going back online
+	var throughput float64 = -1
+	if offline {
+		throughput = 0
+	}
+	return chromedp.Run(b.ctx, network.EmulateNetworkConditions(offline, 0, throughput, throughput))
+}
+
+// SetCacheDisabled toggles the browser cache for every request made
+// by the page. Unlike a hard reload, this persists across navigations.
+func (b *Browser) SetCacheDisabled(disabled bool) error {
+	return chromedp.Run(b.ctx, network.SetCacheDisabled(disabled))
+}
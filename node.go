@@ -0,0 +1,150 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// callFunctionOnNode calls the JavaScript function declaration fn with
+// this bound to node, and decodes its JSON result into res if non-nil.
+func callFunctionOnNode(ctx context.Context, node *cdp.Node, fn string, res interface{}, args ...interface{}) error {
+	obj, err := dom.ResolveNode().WithNodeID(node.NodeID).Do(ctx)
+	if err != nil {
+		return err
+	}
+	defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+
+	callArgs := make([]*runtime.CallArgument, len(args))
+	for i, arg := range args {
+		value, err := json.Marshal(arg)
+		if err != nil {
+			return err
+		}
+		callArgs[i] = &runtime.CallArgument{Value: value}
+	}
+
+	v, exp, err := runtime.CallFunctionOn(fn).
+		WithObjectID(obj.ObjectID).
+		WithArguments(callArgs).
+		WithReturnByValue(true).
+		WithAwaitPromise(true).
+		Do(ctx)
+	if err != nil {
+		return err
+	}
+	if exp != nil {
+		return exp
+	}
+	if res == nil || v.Type == runtime.TypeUndefined {
+		return nil
+	}
+	return json.Unmarshal(v.Value, res)
+}
+
+// EvaluateOnNode locates the first DOM element matching xpath and calls the
+// JavaScript function declaration script with this bound to that element,
+// e.g. `function() { return this.innerText; }`. The returned value is
+// decoded into result if it is non-nil.
+func (b *Browser) EvaluateOnNode(xpath, script string, result interface{}) error {
+	return b.callFunctionOnXPath(xpath, script, result)
+}
+
+// callFunctionOnXPath is like callFunctionOnNode for the first
+// DOM element matching xpath. It returns ErrNotFound rather than
+// waiting if there is no such element.
+func (b *Browser) callFunctionOnXPath(xpath, fn string, res interface{}, args ...interface{}) error {
+	var nodes []*cdp.Node
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	return chromedp.Run(ctx,
+		chromedp.Nodes(xpath, &nodes, chromedp.AtLeast(0)),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			if len(nodes) == 0 {
+				return ErrNotFound
+			}
+			return callFunctionOnNode(ctx, nodes[0], fn, res, args...)
+		}),
+	)
+}
+
+// getXPathJS computes an absolute XPath for the element bound to this.
+// Unprefixed name tests only match HTML elements, so SVG and MathML
+// elements are matched by local-name() instead. Elements outside the
+// document, such as those in a shadow tree, have no XPath.
+const getXPathJS = `function() {
+	var HTML = 'http://www.w3.org/1999/xhtml';
+	function step(element) {
+		if (element.namespaceURI === HTML) {
+			return element.localName;
+		}
+		return "*[local-name()='" + element.localName + "']";
+	}
+	function getXPath(element) {
+		if (element === document.documentElement) {
+			return '/' + step(element);
+		}
+		var index = 1;
+		for (var sibling = element.previousElementSibling; sibling; sibling = sibling.previousElementSibling) {
+			if (sibling.localName === element.localName && sibling.namespaceURI === element.namespaceURI) {
+				index++;
+			}
+		}
+		return getXPath(element.parentElement) + '/' + step(element) + '[' + index + ']';
+	}
+	if (this.getRootNode() !== document) {
+		throw new Error('element is not in the document tree, e.g. it is inside a shadow root');
+	}
+	return getXPath(this);
+}`
+
+// GetXPathForNode returns an absolute XPath for node, such as one
+// returned by GetNodes, which can be passed back into the XPath-based
+// methods of Browser. Nodes inside a shadow tree have no XPath and
+// yield an error.
+func (b *Browser) GetXPathForNode(node *cdp.Node) (string, error) {
+	var xpath string
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		return callFunctionOnNode(ctx, node, getXPathJS, &xpath)
+	}))
+	return xpath, err
+}
+
+// NodeData is a JSON-serialisable representation of a DOM node.
+type NodeData struct {
+	NodeName   string            `json:"nodeName"`
+	NodeValue  string            `json:"nodeValue,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Children   []NodeData        `json:"children,omitempty"`
+}
+
+// ParseNodes converts nodes, such as those returned by GetNodes, into
+// NodeData. Children are only included if chromedp has loaded them.
+func ParseNodes(nodes []*cdp.Node) []NodeData {
+	data := make([]NodeData, 0, len(nodes))
+	for _, node := range nodes {
+		node.RLock()
+		d := NodeData{
+			NodeName:  node.NodeName,
+			NodeValue: node.NodeValue,
+		}
+		if len(node.Attributes) > 0 {
+			d.Attributes = make(map[string]string, len(node.Attributes)/2)
+			for i := 0; i+1 < len(node.Attributes); i += 2 {
+				d.Attributes[node.Attributes[i]] = node.Attributes[i+1]
+			}
+		}
+		children := node.Children
+		node.RUnlock()
+		d.Children = ParseNodes(children)
+		if len(d.Children) == 0 {
+			d.Children = nil
+		}
+		data = append(data, d)
+	}
+	return data
+}
@@ -0,0 +1,126 @@
+package cr
+
+import (
+	"context"
+	"time"
+
+	"github.com/admpub/log"
+	"github.com/chromedp/chromedp"
+)
+
+// BrowserOption configures a Browser created by NewWithOptions.
+type BrowserOption func(*browserOptions)
+
+type browserOptions struct {
+	timeout     time.Duration
+	logger      *log.Logger
+	allocator   []chromedp.ExecAllocatorOption
+	afterLaunch []func(*Browser) error
+	soft        bool
+}
+
+// ViewportSize is the size of the browser viewport in CSS pixels.
+type ViewportSize struct {
+	Width  int
+	Height int
+}
+
+// ContextOptions configures a Browser created by NewContextWithOptions.
+// Zero values leave the corresponding default unchanged.
+type ContextOptions struct {
+	Timeout          time.Duration
+	UserAgent        string
+	Viewport         ViewportSize
+	Logger           *log.Logger
+	AllocatorOptions []chromedp.ExecAllocatorOption
+}
+
+// NewContextWithOptions instantiates a new Chrome browser configured by
+// opts in a single call, as an alternative to calling setters after New.
+func NewContextWithOptions(ctx context.Context, opts ContextOptions) (*Browser, error) {
+	options := []BrowserOption{WithAllocatorOptions(opts.AllocatorOptions...)}
+	if opts.Timeout > 0 {
+		options = append(options, WithTimeout(opts.Timeout))
+	}
+	if opts.Logger != nil {
+		options = append(options, WithLogger(opts.Logger))
+	}
+	if len(opts.UserAgent) > 0 {
+		options = append(options, WithAllocatorOptions(chromedp.UserAgent(opts.UserAgent)))
+	}
+	if opts.Viewport.Width > 0 && opts.Viewport.Height > 0 {
+		options = append(options, WithViewport(opts.Viewport.Width, opts.Viewport.Height))
+	}
+	return NewWithOptions(ctx, options...)
+}
+
+// WithTimeout sets the browser timeout, as SetTimeout does.
+func WithTimeout(d time.Duration) BrowserOption {
+	return func(o *browserOptions) {
+		o.timeout = d
+	}
+}
+
+// WithLogger sets the logger used by the browser.
+func WithLogger(logger *log.Logger) BrowserOption {
+	return func(o *browserOptions) {
+		o.logger = logger
+	}
+}
+
+// WithSoftErrors makes the Must* methods record their error, to be
+// retrieved with GetLastError, instead of ending the program.
+func WithSoftErrors() BrowserOption {
+	return func(o *browserOptions) {
+		o.soft = true
+	}
+}
+
+// WithViewport emulates a viewport of the given size once the browser starts.
+func WithViewport(width, height int) BrowserOption {
+	return func(o *browserOptions) {
+		o.afterLaunch = append(o.afterLaunch, func(b *Browser) error {
+			return b.emulateViewport(int64(width), int64(height))
+		})
+	}
+}
+
+// WithAllocatorOptions passes options through to chromedp's exec allocator.
+func WithAllocatorOptions(args ...chromedp.ExecAllocatorOption) BrowserOption {
+	return func(o *browserOptions) {
+		o.allocator = append(o.allocator, args...)
+	}
+}
+
+// disableAnimationsJS injects a stylesheet that zeroes the duration of
+// all CSS animations and transitions.
+const disableAnimationsJS = `(function() {
+	function inject() {
+		var style = document.createElement('style');
+		style.textContent = '*,*::before,*::after{animation-duration:0s!important;transition-duration:0s!important}';
+		(document.head || document.documentElement).appendChild(style);
+	}
+	if (document.documentElement) {
+		inject();
+	} else {
+		document.addEventListener('DOMContentLoaded', inject);
+	}
+})();`
+
+// WithAnimationsDisabled disables CSS animations and transitions in
+// every page loaded by the browser, preventing timing-dependent
+// failures in screenshot comparison tests.
+func WithAnimationsDisabled() BrowserOption {
+	return func(o *browserOptions) {
+		o.afterLaunch = append(o.afterLaunch, func(b *Browser) error {
+			_, err := b.AddScriptOnLoad(disableAnimationsJS)
+			return err
+		})
+	}
+}
+
+// WithPreciseMemoryInfo makes GetMemoryInfo report exact, rather than
+// quantized, heap sizes.
+func WithPreciseMemoryInfo() BrowserOption {
+	return WithAllocatorOptions(chromedp.Flag(`enable-precise-memory-info`, true))
+}
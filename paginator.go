@@ -0,0 +1,73 @@
+package cr
+
+import (
+	"context"
+	"fmt"
+)
+
+// Paginator walks through paginated content by repeatedly clicking
+// a "next" button. Pair it with ScrapeTable for multi-page extraction.
+type Paginator struct {
+	b         *Browser
+	nextXPath string
+}
+
+// NewPaginator returns a Paginator that uses the element located by
+// nextButtonXPath to advance to the next page.
+func NewPaginator(b *Browser, nextButtonXPath string) *Paginator {
+	return &Paginator{b: b, nextXPath: nextButtonXPath}
+}
+
+var paginatorHasNextJS = `
+	(function() {
+		var el = document.evaluate(%s, document, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+		return !!el && !el.disabled && el.getAttribute('aria-disabled') !== 'true';
+	})();
+	`
+
+var paginatorObserveJS = `
+	(function() {
+		window.__crPaginated = false;
+		new MutationObserver(function() {
+			window.__crPaginated = true;
+		}).observe(document.documentElement, {childList: true, subtree: true, characterData: true});
+	})();
+	`
+
+// paginatorDoneJS is truthy once the page has navigated away and finished
+// loading, or once the DOM of the current page has changed.
+var paginatorDoneJS = `
+	'__crPaginated' in window ? window.__crPaginated : document.readyState === 'complete';
+	`
+
+// Next clicks the "next" button and waits for the resulting navigation
+// or DOM update. It returns false when there is no enabled "next" button,
+// meaning the last page has been reached.
+func (p *Paginator) Next() (bool, error) {
+	var hasNext bool
+	js := fmt.Sprintf(paginatorHasNextJS, jsString(p.nextXPath))
+	if err := p.b.evaluate(js, &hasNext); err != nil {
+		return false, err
+	}
+	if !hasNext {
+		return false, nil
+	}
+	if err := p.b.evaluate(paginatorObserveJS, nil); err != nil {
+		return false, err
+	}
+	if err := p.b.Click(p.nextXPath); err != nil {
+		return false, err
+	}
+	err := p.b.pollUntil(func(ctx context.Context) (bool, error) {
+		var done bool
+		// evaluation may fail while a navigation is in progress; keep polling
+		if err := p.b.evaluateContext(ctx, paginatorDoneJS, &done); err != nil {
+			return false, nil
+		}
+		return done, nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("waiting for next page after clicking %q: %w", p.nextXPath, err)
+	}
+	return true, nil
+}
@@ -0,0 +1,48 @@
+package cr
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/layertree"
+	"github.com/chromedp/cdproto/overlay"
+	"github.com/chromedp/chromedp"
+)
+
+// StartPaintFlashing toggles highlighting of the areas of the page that
+// are repainted, making the rendering cost of updates visible in
+// screenshots and screencasts.
+func (b *Browser) StartPaintFlashing(enabled bool) error {
+	return chromedp.Run(b.ctx,
+		dom.Enable(),
+		overlay.Enable(),
+		overlay.SetShowPaintRects(enabled),
+	)
+}
+
+// GetLayerTree returns the compositing layers of the current page,
+// which helps to identify expensive composite layers.
+func (b *Browser) GetLayerTree() ([]*layertree.Layer, error) {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	layers := make(chan []*layertree.Layer, 1)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if e, ok := ev.(*layertree.EventLayerTreeDidChange); ok {
+			select {
+			case layers <- e.Layers:
+			default:
+			}
+		}
+	})
+	// enabling the domain reports the current layer tree
+	if err := chromedp.Run(b.ctx, layertree.Enable()); err != nil {
+		return nil, err
+	}
+	defer chromedp.Run(b.ctx, layertree.Disable())
+	select {
+	case l := <-layers:
+		return l, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
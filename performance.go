@@ -0,0 +1,140 @@
+package cr
+
+import "fmt"
+
+// PerformanceTiming mirrors window.performance.timing. All values are
+// milliseconds since the UNIX epoch, or zero if the event has not occurred.
+type PerformanceTiming struct {
+	NavigationStart            int64 `json:"navigationStart"`
+	UnloadEventStart           int64 `json:"unloadEventStart"`
+	UnloadEventEnd             int64 `json:"unloadEventEnd"`
+	RedirectStart              int64 `json:"redirectStart"`
+	RedirectEnd                int64 `json:"redirectEnd"`
+	FetchStart                 int64 `json:"fetchStart"`
+	DomainLookupStart          int64 `json:"domainLookupStart"`
+	DomainLookupEnd            int64 `json:"domainLookupEnd"`
+	ConnectStart               int64 `json:"connectStart"`
+	ConnectEnd                 int64 `json:"connectEnd"`
+	SecureConnectionStart      int64 `json:"secureConnectionStart"`
+	RequestStart               int64 `json:"requestStart"`
+	ResponseStart              int64 `json:"responseStart"`
+	ResponseEnd                int64 `json:"responseEnd"`
+	DomLoading                 int64 `json:"domLoading"`
+	DomInteractive             int64 `json:"domInteractive"`
+	DomContentLoadedEventStart int64 `json:"domContentLoadedEventStart"`
+	DomContentLoadedEventEnd   int64 `json:"domContentLoadedEventEnd"`
+	DomComplete                int64 `json:"domComplete"`
+	LoadEventStart             int64 `json:"loadEventStart"`
+	LoadEventEnd               int64 `json:"loadEventEnd"`
+}
+
+// GetNavigationTiming returns the Navigation Timing data of the current page.
+func (b *Browser) GetNavigationTiming() (*PerformanceTiming, error) {
+	timing := &PerformanceTiming{}
+	err := b.evaluate(`window.performance.timing.toJSON()`, timing)
+	return timing, err
+}
+
+// ResourceTiming is a Resource Timing entry for a sub-resource of the page.
+// Duration is in milliseconds and TransferSize in bytes.
+type ResourceTiming struct {
+	Name          string  `json:"name"`
+	InitiatorType string  `json:"initiatorType"`
+	Duration      float64 `json:"duration"`
+	TransferSize  int64   `json:"transferSize"`
+}
+
+// GetResourceTimings returns the Resource Timing entries of every
+// sub-resource loaded by the current page.
+func (b *Browser) GetResourceTimings() ([]ResourceTiming, error) {
+	var timings []ResourceTiming
+	err := b.evaluate(`performance.getEntriesByType('resource').map(function(e) { return e.toJSON(); })`, &timings)
+	return timings, err
+}
+
+// performanceEntriesJS returns the entries of a type as JSON. Some types,
+// such as "longtask" and "largest-contentful-paint", are only exposed to
+// observers, so those are read from a buffered PerformanceObserver.
+var performanceEntriesJS = `
+	(function(type) {
+		var entries = performance.getEntriesByType(type);
+		var observable = PerformanceObserver.supportedEntryTypes || [];
+		if (entries.length === 0 && observable.indexOf(type) >= 0) {
+			var observer = new PerformanceObserver(function() {});
+			observer.observe({type: type, buffered: true});
+			entries = observer.takeRecords();
+			observer.disconnect();
+		}
+		return entries.map(function(e) { return e.toJSON(); });
+	})(%s);
+	`
+
+// GetPerformanceEntries returns the performance entries of entryType,
+// e.g. "mark", "measure", "paint", "longtask" or "largest-contentful-paint".
+func (b *Browser) GetPerformanceEntries(entryType string) ([]map[string]interface{}, error) {
+	var entries []map[string]interface{}
+	err := b.evaluate(fmt.Sprintf(performanceEntriesJS, jsString(entryType)), &entries)
+	return entries, err
+}
+
+// MarkPerformance records a performance mark called name, which shows up
+// in GetPerformanceEntries("mark") and in traces alongside the page's own.
+func (b *Browser) MarkPerformance(name string) error {
+	return b.evaluate(fmt.Sprintf(`performance.mark(%s), undefined`, jsString(name)), nil)
+}
+
+// MeasurePerformance records a performance measure called name between
+// the marks startMark and endMark. An empty startMark measures from the
+// start of navigation and an empty endMark measures up to now.
+func (b *Browser) MeasurePerformance(name, startMark, endMark string) error {
+	mark := func(s string) string {
+		if len(s) == 0 {
+			return `undefined`
+		}
+		return jsString(s)
+	}
+	js := fmt.Sprintf(`performance.measure(%s, %s, %s), undefined`, jsString(name), mark(startMark), mark(endMark))
+	return b.evaluate(js, nil)
+}
+
+// MemoryInfo holds JavaScript heap statistics, in bytes.
+type MemoryInfo struct {
+	JSHeapSizeLimit uint64 `json:"jsHeapSizeLimit"`
+	TotalJSHeapSize uint64 `json:"totalJSHeapSize"`
+	UsedJSHeapSize  uint64 `json:"usedJSHeapSize"`
+}
+
+var memoryInfoJS = `
+	(function() {
+		var m = window.performance.memory;
+		return {
+			jsHeapSizeLimit: m.jsHeapSizeLimit,
+			totalJSHeapSize: m.totalJSHeapSize,
+			usedJSHeapSize: m.usedJSHeapSize
+		};
+	})();
+	`
+
+// GetMemoryInfo returns the JavaScript heap statistics of the page from
+// window.performance.memory. Chrome quantizes these values unless the
+// browser was created with WithPreciseMemoryInfo.
+func (b *Browser) GetMemoryInfo() (*MemoryInfo, error) {
+	info := &MemoryInfo{}
+	err := b.evaluate(memoryInfoJS, info)
+	return info, err
+}
+
+// GetNodeCount returns the number of elements in the document, which
+// is useful for detecting DOM bloat.
+func (b *Browser) GetNodeCount() (int, error) {
+	var count int
+	err := b.evaluate(`document.querySelectorAll('*').length`, &count)
+	return count, err
+}
+
+// GetNodesByTagName returns the number of elements with the given tag name.
+func (b *Browser) GetNodesByTagName(tag string) (int, error) {
+	var count int
+	err := b.evaluate(`document.getElementsByTagName(`+jsString(tag)+`).length`, &count)
+	return count, err
+}
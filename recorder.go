@@ -0,0 +1,151 @@
+package cr
+
+import (
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// RecordedAction is a single call captured by a Recorder.
+type RecordedAction struct {
+	Method string
+	Args   []interface{}
+
+	run func(*Browser) error
+}
+
+// String returns a readable representation of the action.
+func (a RecordedAction) String() string {
+	return fmt.Sprintf("%s%v", a.Method, a.Args)
+}
+
+// Recorder wraps a *Browser and records every action performed
+// through it so the sequence can be inspected or replayed later.
+// Only the methods of Recorder are recorded; calls made directly
+// on the wrapped *Browser are not.
+type Recorder struct {
+	b       *Browser
+	Actions []RecordedAction
+}
+
+// NewRecorder returns a Recorder that delegates to b.
+func NewRecorder(b *Browser) *Recorder {
+	return &Recorder{b: b}
+}
+
+func (r *Recorder) record(method string, run func(*Browser) error, args ...interface{}) error {
+	action := RecordedAction{Method: method, Args: args, run: run}
+	r.Actions = append(r.Actions, action)
+	r.b.logger.Debugf("Record: %s", action)
+	return run(r.b)
+}
+
+// Replay performs the recorded actions, in order, against b.
+func (r *Recorder) Replay(b *Browser) error {
+	for i, action := range r.Actions {
+		if err := action.run(b); err != nil {
+			return fmt.Errorf("replay action %d (%s): %w", i, action, err)
+		}
+	}
+	return nil
+}
+
+// Reset discards all recorded actions.
+func (r *Recorder) Reset() {
+	r.Actions = nil
+}
+
+// Navigate records and performs Browser.Navigate.
+func (r *Recorder) Navigate(url string, otherActions ...chromedp.Action) error {
+	return r.record(`Navigate`, func(b *Browser) error {
+		return b.Navigate(url, otherActions...)
+	}, url)
+}
+
+// MustNavigate records and performs Browser.Navigate,
+// ending execution on error like Browser.MustNavigate.
+func (r *Recorder) MustNavigate(url string, otherActions ...chromedp.Action) {
+	if err := r.Navigate(url, otherActions...); err != nil {
+		r.b.fatalf("Failed to navigate to %q: %s\n", url, err)
+	}
+}
+
+// SendKeys records and performs Browser.SendKeys.
+func (r *Recorder) SendKeys(xpath, value string) error {
+	return r.record(`SendKeys`, func(b *Browser) error {
+		return b.SendKeys(xpath, value)
+	}, xpath, value)
+}
+
+// MustSendKeys records and performs Browser.SendKeys,
+// ending execution on error like Browser.MustSendKeys.
+func (r *Recorder) MustSendKeys(xpath, value string) {
+	if err := r.SendKeys(xpath, value); err != nil {
+		r.b.fatalf("Failed to send %q to %q: %s\n", value, xpath, err)
+	}
+}
+
+// Click records and performs Browser.Click.
+func (r *Recorder) Click(xpath string) error {
+	return r.record(`Click`, func(b *Browser) error {
+		return b.Click(xpath)
+	}, xpath)
+}
+
+// MustClick records and performs Browser.Click,
+// ending execution on error like Browser.MustClick.
+func (r *Recorder) MustClick(xpath string) {
+	if err := r.Click(xpath); err != nil {
+		r.b.fatalf("Failed to click %q: %s\n", xpath, err)
+	}
+}
+
+// ClickByXY records and performs Browser.ClickByXY.
+func (r *Recorder) ClickByXY(xpath string) error {
+	return r.record(`ClickByXY`, func(b *Browser) error {
+		return b.ClickByXY(xpath)
+	}, xpath)
+}
+
+// RunAction records and performs Browser.RunAction.
+func (r *Recorder) RunAction(action chromedp.Action) error {
+	return r.record(`RunAction`, func(b *Browser) error {
+		return b.RunAction(action)
+	}, action)
+}
+
+// RunTasks records and performs Browser.RunTasks.
+func (r *Recorder) RunTasks(actions ...chromedp.Action) error {
+	return r.record(`RunTasks`, func(b *Browser) error {
+		return b.RunTasks(actions...)
+	}, actions)
+}
+
+// SetWindowSize records and performs Browser.SetWindowSize.
+func (r *Recorder) SetWindowSize(width, height int) error {
+	return r.record(`SetWindowSize`, func(b *Browser) error {
+		return b.SetWindowSize(width, height)
+	}, width, height)
+}
+
+// SetWindowPosition records and performs Browser.SetWindowPosition.
+func (r *Recorder) SetWindowPosition(x, y int) error {
+	return r.record(`SetWindowPosition`, func(b *Browser) error {
+		return b.SetWindowPosition(x, y)
+	}, x, y)
+}
+
+// MaximizeWindow records and performs Browser.MaximizeWindow.
+func (r *Recorder) MaximizeWindow() error {
+	return r.record(`MaximizeWindow`, (*Browser).MaximizeWindow)
+}
+
+// MinimizeWindow records and performs Browser.MinimizeWindow.
+func (r *Recorder) MinimizeWindow() error {
+	return r.record(`MinimizeWindow`, (*Browser).MinimizeWindow)
+}
+
+// FullscreenWindow records and performs Browser.FullscreenWindow.
+func (r *Recorder) FullscreenWindow() error {
+	return r.record(`FullscreenWindow`, (*Browser).FullscreenWindow)
+}
@@ -0,0 +1,191 @@
+package cr
+
+import (
+	"fmt"
+)
+
+// FieldInfo describes a single form control.
+type FieldInfo struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	Required bool   `json:"required"`
+}
+
+// FormInfo describes a <form> element and its controls.
+type FormInfo struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Action string      `json:"action"`
+	Method string      `json:"method"`
+	Fields []FieldInfo `json:"fields"`
+}
+
+// getAllFormsJS reads form attributes rather than properties, since a
+// control named e.g. "action" shadows the property of the same name.
+var getAllFormsJS = `
+	(function() {
+		var elements = Object.getOwnPropertyDescriptor(HTMLFormElement.prototype, 'elements').get;
+		function action(form) {
+			try {
+				return new URL(form.getAttribute('action') || '', document.baseURI).href;
+			} catch (e) {
+				return form.getAttribute('action') || '';
+			}
+		}
+		return Array.from(document.forms).map(function(form) {
+			return {
+				id: form.getAttribute('id') || '',
+				name: form.getAttribute('name') || '',
+				action: action(form),
+				method: (form.getAttribute('method') || 'get').toLowerCase(),
+				fields: Array.from(elements.call(form)).map(function(el) {
+					return {
+						name: el.name || '',
+						type: el.type || el.tagName.toLowerCase(),
+						value: el.value || '',
+						required: !!el.required
+					};
+				})
+			};
+		});
+	})();
+	`
+
+// GetAllForms returns the forms on the current page along with their fields.
+func (b *Browser) GetAllForms() ([]FormInfo, error) {
+	var forms []FormInfo
+	err := b.evaluate(getAllFormsJS, &forms)
+	return forms, err
+}
+
+// TableOptions controls how ScrapeTable extracts rows.
+type TableOptions struct {
+	// ExcludeHeader drops rows from the table's <thead>.
+	ExcludeHeader bool
+}
+
+var scrapeTableJS = `function(excludeHeader) {
+	var rows = [];
+	for (var i = 0; i < this.rows.length; i++) {
+		var row = this.rows[i];
+		if (excludeHeader && row.parentNode.tagName === 'THEAD') {
+			continue;
+		}
+		var cells = [];
+		for (var j = 0; j < row.cells.length; j++) {
+			cells.push(row.cells[j].innerText.trim());
+		}
+		rows.push(cells);
+	}
+	return rows;
+}`
+
+// ScrapeTable returns the text of every cell in the <table> located by
+// tableXPath, one slice per row. Header rows are included unless opts
+// is given with ExcludeHeader set.
+func (b *Browser) ScrapeTable(tableXPath string, opts ...TableOptions) ([][]string, error) {
+	var o TableOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	var rows [][]string
+	err := b.callFunctionOnXPath(tableXPath, scrapeTableJS, &rows, o.ExcludeHeader)
+	return rows, err
+}
+
+var metaTagsJS = `
+	(function() {
+		var tags = {};
+		document.querySelectorAll('meta[%[1]s]').forEach(function(meta) {
+			tags[meta.getAttribute('%[1]s')] = meta.getAttribute('content') || '';
+		});
+		return tags;
+	})();
+	`
+
+func (b *Browser) getMetaTags(attr string) (map[string]string, error) {
+	tags := make(map[string]string)
+	js := fmt.Sprintf(metaTagsJS, attr)
+	err := b.evaluate(js, &tags)
+	return tags, err
+}
+
+// GetMetaTags returns the content of every <meta name="..."> tag, keyed by name.
+func (b *Browser) GetMetaTags() (map[string]string, error) {
+	return b.getMetaTags(`name`)
+}
+
+// GetOpenGraphTags returns the content of every <meta property="..."> tag,
+// such as og:title, keyed by property.
+func (b *Browser) GetOpenGraphTags() (map[string]string, error) {
+	return b.getMetaTags(`property`)
+}
+
+var faviconURLJS = `
+	(function() {
+		var link = document.querySelector('link[rel~="icon"]') || document.querySelector('link[rel="shortcut icon"]');
+		if (link) {
+			return link.href;
+		}
+		try {
+			var fallback = new URL('/favicon.ico', document.baseURI);
+			return /^https?:$/.test(fallback.protocol) ? fallback.href : '';
+		} catch (e) {
+			// e.g. about:blank and data: pages have no origin to resolve against
+			return '';
+		}
+	})();
+	`
+
+// GetFaviconURL returns the URL of the page's favicon. If none is declared
+// it returns the conventional /favicon.ico of the page's origin, without
+// checking that it exists, or an empty string if the page has no origin.
+func (b *Browser) GetFaviconURL() (string, error) {
+	var href string
+	err := b.evaluate(faviconURLJS, &href)
+	return href, err
+}
+
+var canonicalURLJS = `
+	(function() {
+		var link = document.querySelector('link[rel="canonical"]');
+		return link ? link.href : '';
+	})();
+	`
+
+// GetCanonicalURL returns the href of the page's canonical link,
+// or ErrNotFound if the page does not declare one.
+func (b *Browser) GetCanonicalURL() (string, error) {
+	var href string
+	if err := b.evaluate(canonicalURLJS, &href); err != nil {
+		return "", err
+	}
+	if len(href) == 0 {
+		return "", ErrNotFound
+	}
+	return href, nil
+}
+
+var captureFormValuesJS = `function() {
+	var values = {};
+	this.querySelectorAll('input, select, textarea').forEach(function(el) {
+		if (!el.name) {
+			return;
+		}
+		if ((el.type === 'checkbox' || el.type === 'radio') && !el.checked) {
+			return;
+		}
+		values[el.name] = el.value;
+	});
+	return values;
+}`
+
+// CaptureFormValues returns the current value of every named <input>,
+// <select> and <textarea> inside the form located by formXPath.
+// Unchecked checkboxes and radio buttons are omitted.
+func (b *Browser) CaptureFormValues(formXPath string) (map[string]string, error) {
+	values := make(map[string]string)
+	err := b.EvaluateOnNode(formXPath, captureFormValuesJS, &values)
+	return values, err
+}
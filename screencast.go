@@ -0,0 +1,225 @@
+package cr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	_ "image/jpeg" // register JPEG decoder for screencast frames
+	_ "image/png"  // register PNG decoder for screencast frames
+	"os"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// StartScreencast starts streaming frames of the page. format is either
+// "jpeg" or "png"; quality only applies to JPEG. Frames are delivered on
+// the returned channel and are dropped if the receiver falls behind.
+// Call stop to end the screencast and close the channel.
+func (b *Browser) StartScreencast(format string, quality int, maxWidth, maxHeight int) (<-chan []byte, func(), error) {
+	frames := make(chan []byte, 64)
+	ctx, cancel := context.WithCancel(b.ctx)
+	var (
+		mu      sync.Mutex
+		stopped bool
+	)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		e, ok := ev.(*page.EventScreencastFrame)
+		if !ok {
+			return
+		}
+		go func() {
+			if err := page.ScreencastFrameAck(e.SessionID).Do(b.executor()); err != nil {
+				b.logger.Errorf("Failed to acknowledge screencast frame: %v", err)
+			}
+		}()
+		data, err := base64.StdEncoding.DecodeString(e.Data)
+		if err != nil {
+			b.logger.Errorf("Failed to decode screencast frame: %v", err)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		select {
+		case frames <- data:
+		default:
+		}
+	})
+	err := chromedp.Run(b.ctx, page.StartScreencast().
+		WithFormat(page.ScreencastFormat(format)).
+		WithQuality(int64(quality)).
+		WithMaxWidth(int64(maxWidth)).
+		WithMaxHeight(int64(maxHeight)))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	stop := func() {
+		mu.Lock()
+		if stopped {
+			mu.Unlock()
+			return
+		}
+		stopped = true
+		close(frames)
+		mu.Unlock()
+		cancel()
+		if err := chromedp.Run(b.ctx, page.StopScreencast()); err != nil {
+			b.logger.Errorf("Failed to stop screencast: %v", err)
+		}
+	}
+	return frames, stop, nil
+}
+
+// SaveScreencastToGIF encodes frames captured by StartScreencast as an
+// animated GIF played back at fps frames per second and writes it to path.
+func SaveScreencastToGIF(frames [][]byte, path string, fps int) error {
+	anim, err := encodeGIF(frames, fps, nil)
+	if err != nil {
+		return err
+	}
+	return writeGIF(anim, path)
+}
+
+func writeGIF(anim *gif.GIF, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeGIF builds an animation from frames. If crop is non-nil it maps
+// the bounds of each frame to the region to keep.
+func encodeGIF(frames [][]byte, fps int, crop func(image.Rectangle) image.Rectangle) (*gif.GIF, error) {
+	if fps < 1 {
+		fps = 1
+	}
+	delay := 100 / fps
+	anim := &gif.GIF{}
+	for _, frame := range frames {
+		img, _, err := image.Decode(bytes.NewReader(frame))
+		if err != nil {
+			return nil, err
+		}
+		r := img.Bounds()
+		if crop != nil {
+			r = crop(r).Intersect(img.Bounds())
+		}
+		paletted := image.NewPaletted(image.Rect(0, 0, r.Dx(), r.Dy()), palette.Plan9)
+		draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, r.Min)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return anim, nil
+}
+
+// elementRect is the bounding box of an element relative to a viewport
+// of ViewportWidth CSS pixels.
+type elementRect struct {
+	X             float64 `json:"x"`
+	Y             float64 `json:"y"`
+	Width         float64 `json:"width"`
+	Height        float64 `json:"height"`
+	ViewportWidth float64 `json:"viewportWidth"`
+}
+
+var elementRectJS = `function() {
+	var r = this.getBoundingClientRect();
+	return {x: r.x, y: r.y, width: r.width, height: r.height, viewportWidth: window.innerWidth};
+}`
+
+// waitForStableRect waits until the element located by xpath exists and
+// its bounding box stops moving between two polls, and returns it.
+func (b *Browser) waitForStableRect(xpath string) (*elementRect, error) {
+	var last *elementRect
+	err := b.pollUntil(func(ctx context.Context) (bool, error) {
+		rect := &elementRect{}
+		err := b.callFunctionOnXPath(xpath, elementRectJS, rect)
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		stable := last != nil && *last == *rect
+		last = rect
+		return stable, nil
+	})
+	return last, err
+}
+
+// RecordElement records the element located by xpath for duration, once
+// it has stopped moving, and returns the recording as an animated GIF.
+func (b *Browser) RecordElement(xpath string, duration time.Duration) ([]byte, error) {
+	anim, err := b.recordElement(xpath, duration)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RecordElementToFile is like RecordElement but writes the GIF to path.
+func (b *Browser) RecordElementToFile(xpath, path string, duration time.Duration) error {
+	anim, err := b.recordElement(xpath, duration)
+	if err != nil {
+		return err
+	}
+	return writeGIF(anim, path)
+}
+
+func (b *Browser) recordElement(xpath string, duration time.Duration) (*gif.GIF, error) {
+	rect, err := b.waitForStableRect(xpath)
+	if err != nil {
+		return nil, err
+	}
+	stream, stop, err := b.StartScreencast(`png`, 100, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	var frames [][]byte
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+collect:
+	for {
+		select {
+		case frame := <-stream:
+			frames = append(frames, frame)
+		case <-timer.C:
+			break collect
+		}
+	}
+	stop()
+	if len(frames) == 0 {
+		return nil, errors.New("no screencast frames were captured")
+	}
+	fps := int(float64(len(frames)) / duration.Seconds())
+	return encodeGIF(frames, fps, func(bounds image.Rectangle) image.Rectangle {
+		// frames may be scaled relative to CSS pixels
+		scale := float64(bounds.Dx()) / rect.ViewportWidth
+		return image.Rect(
+			int(rect.X*scale),
+			int(rect.Y*scale),
+			int((rect.X+rect.Width)*scale),
+			int((rect.Y+rect.Height)*scale),
+		)
+	})
+}
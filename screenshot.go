@@ -0,0 +1,189 @@
+package cr
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// ViewportScreenshot takes a screenshot of the current page as shown in
+// the viewport. Unlike Screenshot it neither navigates nor resizes.
+func (b *Browser) ViewportScreenshot(quality int64) ([]byte, error) {
+	var buf []byte
+	err := chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		buf, err = page.CaptureScreenshot().WithQuality(quality).Do(ctx)
+		return err
+	}))
+	return buf, err
+}
+
+var addAnnotationJS = `
+	(function(label) {
+		var div = document.createElement('div');
+		div.id = '__cr_annotation';
+		div.textContent = label;
+		div.style.cssText = 'position:fixed;top:0;left:0;z-index:2147483647;padding:4px 8px;' +
+			'background:rgba(0,0,0,0.75);color:#fff;font:bold 14px sans-serif;pointer-events:none;';
+		document.documentElement.appendChild(div);
+	})(%s);
+	`
+
+var removeAnnotationJS = `
+	(function() {
+		var div = document.getElementById('__cr_annotation');
+		if (div) {
+			div.remove();
+		}
+	})();
+	`
+
+// TakeAnnotatedScreenshot takes a screenshot of the current viewport with
+// label drawn in its top-left corner, producing self-documenting images
+// for bug reports and step-by-step guides.
+func (b *Browser) TakeAnnotatedScreenshot(label string, quality int64) ([]byte, error) {
+	if err := b.evaluate(fmt.Sprintf(addAnnotationJS, jsString(label)), nil); err != nil {
+		return nil, err
+	}
+	buf, err := b.ViewportScreenshot(quality)
+	if rerr := b.evaluate(removeAnnotationJS, nil); err == nil {
+		err = rerr
+	}
+	return buf, err
+}
+
+// GetSVGScreenshot renders the <svg> element located by svgXPath on its
+// own in a temporary tab and returns a PNG screenshot of it.
+func (b *Browser) GetSVGScreenshot(svgXPath string) ([]byte, error) {
+	var svg string
+	err := b.EvaluateOnNode(svgXPath, `function() { return new XMLSerializer().serializeToString(this); }`, &svg)
+	if err != nil {
+		return nil, err
+	}
+	html := `<!DOCTYPE html><html><body style="margin:0">` + svg + `</body></html>`
+	dataURI := `data:text/html;base64,` + base64.StdEncoding.EncodeToString([]byte(html))
+
+	ctx, cancel := chromedp.NewContext(b.taskCtx)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, b.timeout)
+	defer cancelTimeout()
+	var buf []byte
+	err = chromedp.Run(ctx,
+		chromedp.Navigate(dataURI),
+		chromedp.Screenshot(`svg`, &buf, chromedp.NodeVisible, chromedp.ByQuery),
+	)
+	return buf, err
+}
+
+// ErrScreenshotMismatch is returned by CompareScreenshot when a
+// screenshot differs from its baseline by more than the threshold.
+var ErrScreenshotMismatch = errors.New("screenshot does not match baseline")
+
+// CompareScreenshot takes a screenshot of the element located by xpath and
+// compares it with the PNG at baselinePath. It returns the difference as a
+// score from 0, identical, to 1, every channel of every pixel inverted.
+// If the score exceeds threshold it also returns ErrScreenshotMismatch and
+// writes an image highlighting the differences next to the baseline, with
+// a "-diff" suffix, e.g. "button.png" becomes "button-diff.png".
+func (b *Browser) CompareScreenshot(xpath string, baselinePath string, threshold float64) (float64, error) {
+	var buf []byte
+	if err := chromedp.Run(b.ctx, chromedp.Screenshot(xpath, &buf, chromedp.NodeVisible)); err != nil {
+		return 0, err
+	}
+	actual, err := png.Decode(bytes.NewReader(buf))
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(baselinePath)
+	if err != nil {
+		return 0, err
+	}
+	baseline, err := png.Decode(f)
+	f.Close()
+	if err != nil {
+		return 0, err
+	}
+	score, diff := diffImages(baseline, actual)
+	if score <= threshold {
+		return score, nil
+	}
+	ext := filepath.Ext(baselinePath)
+	diffPath := strings.TrimSuffix(baselinePath, ext) + `-diff.png`
+	if err := writePNG(diffPath, diff); err != nil {
+		b.logger.Errorf("Failed to write diff image %s: %v", diffPath, err)
+	}
+	return score, fmt.Errorf("%w: score %.4f exceeds %.4f, see %s", ErrScreenshotMismatch, score, threshold, diffPath)
+}
+
+// diffImages returns the normalised difference between img1 and img2 and an
+// image marking differing pixels in red over a faded copy of img1. Pixels
+// covered by only one of the images count as completely different.
+func diffImages(img1, img2 image.Image) (float64, *image.RGBA) {
+	ab, bb := img1.Bounds(), img2.Bounds()
+	w, h := ab.Dx(), ab.Dy()
+	if bb.Dx() > w {
+		w = bb.Dx()
+	}
+	if bb.Dy() > h {
+		h = bb.Dy()
+	}
+	diff := image.NewRGBA(image.Rect(0, 0, w, h))
+	if w == 0 || h == 0 {
+		return 0, diff
+	}
+	red := color.RGBA{R: 0xff, A: 0xff}
+	var total float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			pa := image.Pt(ab.Min.X+x, ab.Min.Y+y)
+			pb := image.Pt(bb.Min.X+x, bb.Min.Y+y)
+			if !pa.In(ab) || !pb.In(bb) {
+				total += 3
+				diff.Set(x, y, red)
+				continue
+			}
+			r1, g1, b1, _ := img1.At(pa.X, pa.Y).RGBA()
+			r2, g2, b2, _ := img2.At(pb.X, pb.Y).RGBA()
+			d := (absDiff(r1, r2) + absDiff(g1, g2) + absDiff(b1, b2)) / 0xffff
+			total += d
+			if d > 0 {
+				diff.Set(x, y, red)
+				continue
+			}
+			gray := uint8((r1 + g1 + b1) / 3 >> 8)
+			faded := 0xc0 + gray/4
+			diff.Set(x, y, color.RGBA{R: faded, G: faded, B: faded, A: 0xff})
+		}
+	}
+	return total / float64(w*h*3), diff
+}
+
+func absDiff(a, b uint32) float64 {
+	if a > b {
+		return float64(a - b)
+	}
+	return float64(b - a)
+}
+
+func writePNG(path string, img image.Image) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, img); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
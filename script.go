@@ -0,0 +1,88 @@
+package cr
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// ScriptStep is a single step of a script run by RunScript.
+type ScriptStep interface {
+	Run(*Browser) error
+}
+
+// ScriptError reports the step at which RunScript failed.
+type ScriptError struct {
+	Index int
+	Step  ScriptStep
+	Err   error
+}
+
+func (e *ScriptError) Error() string {
+	return fmt.Sprintf("step %d (%T): %v", e.Index, e.Step, e.Err)
+}
+
+func (e *ScriptError) Unwrap() error {
+	return e.Err
+}
+
+// RunScript runs steps in order, stopping at the first failure,
+// which is returned as a *ScriptError.
+func (b *Browser) RunScript(steps []ScriptStep) error {
+	for i, step := range steps {
+		if err := step.Run(b); err != nil {
+			return &ScriptError{Index: i, Step: step, Err: err}
+		}
+	}
+	return nil
+}
+
+// NavigateStep sends the browser to URL.
+type NavigateStep struct {
+	URL string
+}
+
+// Run implements ScriptStep.
+func (s NavigateStep) Run(b *Browser) error {
+	return b.Navigate(s.URL)
+}
+
+// ClickStep clicks the element located by XPath.
+type ClickStep struct {
+	XPath string
+}
+
+// Run implements ScriptStep.
+func (s ClickStep) Run(b *Browser) error {
+	return b.Click(s.XPath)
+}
+
+// SendKeysStep sends Value as keystrokes to the element located by XPath.
+type SendKeysStep struct {
+	XPath string
+	Value string
+}
+
+// Run implements ScriptStep.
+func (s SendKeysStep) Run(b *Browser) error {
+	return b.SendKeys(s.XPath, s.Value)
+}
+
+// WaitStep waits for the element located by XPath, if set, to become
+// visible and then pauses for Duration.
+type WaitStep struct {
+	XPath    string
+	Duration time.Duration
+}
+
+// Run implements ScriptStep.
+func (s WaitStep) Run(b *Browser) error {
+	if len(s.XPath) > 0 {
+		if err := b.WaitForSelector(s.XPath, chromedp.BySearch); err != nil {
+			return err
+		}
+	}
+	time.Sleep(s.Duration)
+	return nil
+}
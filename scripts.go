@@ -0,0 +1,45 @@
+package cr
+
+import (
+	"context"
+	"sync"
+
+	"github.com/chromedp/cdproto/debugger"
+	"github.com/chromedp/chromedp"
+)
+
+// GetLoadedScriptURLs returns the URL of every script parsed by the
+// current page, including scripts injected by third parties. Inline
+// scripts without a URL are omitted.
+func (b *Browser) GetLoadedScriptURLs() ([]string, error) {
+	ctx, cancel := context.WithCancel(b.ctx)
+	defer cancel()
+	var (
+		mu   sync.Mutex
+		urls []string
+		seen = make(map[string]bool)
+	)
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		e, ok := ev.(*debugger.EventScriptParsed)
+		if !ok || len(e.URL) == 0 {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !seen[e.URL] {
+			seen[e.URL] = true
+			urls = append(urls, e.URL)
+		}
+	})
+	// enabling the debugger reports every script already parsed
+	err := chromedp.Run(b.ctx,
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			_, err := debugger.Enable().Do(ctx)
+			return err
+		}),
+		debugger.Disable(),
+	)
+	mu.Lock()
+	defer mu.Unlock()
+	return urls, err
+}
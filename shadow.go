@@ -0,0 +1,134 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/dom"
+	"github.com/chromedp/cdproto/runtime"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrNoShadowRoot is returned when an element has no open shadow root.
+var ErrNoShadowRoot = errors.New("element has no open shadow root")
+
+// ShadowBrowser performs XPath-based interactions inside the shadow DOM
+// of a host element. XPaths are evaluated relative to the shadow root,
+// so they should be relative expressions such as ".//button".
+type ShadowBrowser struct {
+	b         *Browser
+	hostXPath string
+}
+
+// GetShadowRoot returns a *ShadowBrowser for the open shadow root of the
+// element located by hostXPath.
+func (b *Browser) GetShadowRoot(hostXPath string) (*ShadowBrowser, error) {
+	var hasRoot bool
+	err := b.EvaluateOnNode(hostXPath, `function() { return !!this.shadowRoot; }`, &hasRoot)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRoot {
+		return nil, ErrNoShadowRoot
+	}
+	return &ShadowBrowser{b: b, hostXPath: hostXPath}, nil
+}
+
+var shadowQueryJS = `function(xpath) {
+	if (!this.shadowRoot) {
+		return null;
+	}
+	return document.evaluate(xpath, this.shadowRoot, null, XPathResult.FIRST_ORDERED_NODE_TYPE, null).singleNodeValue;
+}`
+
+// nodeIDs resolves xpath inside the shadow root to a DOM node ID that
+// can be used with chromedp.ByNodeID.
+func (s *ShadowBrowser) nodeIDs(ctx context.Context, xpath string) ([]cdp.NodeID, error) {
+	var hosts []*cdp.Node
+	if err := chromedp.Nodes(s.hostXPath, &hosts).Do(ctx); err != nil {
+		return nil, err
+	}
+	if len(hosts) == 0 {
+		return nil, ErrNotFound
+	}
+	host, err := dom.ResolveNode().WithNodeID(hosts[0].NodeID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer runtime.ReleaseObject(host.ObjectID).Do(ctx)
+
+	arg, err := json.Marshal(xpath)
+	if err != nil {
+		return nil, err
+	}
+	obj, exp, err := runtime.CallFunctionOn(shadowQueryJS).
+		WithObjectID(host.ObjectID).
+		WithArguments([]*runtime.CallArgument{{Value: arg}}).
+		Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if exp != nil {
+		return nil, exp
+	}
+	if obj.ObjectID == "" || obj.Subtype == runtime.SubtypeNull {
+		return nil, ErrNotFound
+	}
+	defer runtime.ReleaseObject(obj.ObjectID).Do(ctx)
+
+	id, err := dom.RequestNode(obj.ObjectID).Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return []cdp.NodeID{id}, nil
+}
+
+// run resolves xpath and passes the resulting node IDs to fn.
+func (s *ShadowBrowser) run(xpath string, fn func(ctx context.Context, ids []cdp.NodeID) error) error {
+	return chromedp.Run(s.b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		ids, err := s.nodeIDs(ctx, xpath)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, ids)
+	}))
+}
+
+// FindElement attempts to locate a DOM element inside the shadow root.
+func (s *ShadowBrowser) FindElement(xpath string) error {
+	return s.run(xpath, func(context.Context, []cdp.NodeID) error {
+		return nil
+	})
+}
+
+// SendKeys sends keystrokes to a DOM element inside the shadow root.
+func (s *ShadowBrowser) SendKeys(xpath, value string) error {
+	return s.run(xpath, func(ctx context.Context, ids []cdp.NodeID) error {
+		return chromedp.SendKeys(ids, value, chromedp.ByNodeID).Do(ctx)
+	})
+}
+
+// Click performs a mouse click on a DOM element inside the shadow root.
+func (s *ShadowBrowser) Click(xpath string) error {
+	return s.run(xpath, func(ctx context.Context, ids []cdp.NodeID) error {
+		return chromedp.Click(ids, chromedp.ByNodeID).Do(ctx)
+	})
+}
+
+// GetAttributes returns the HTML attributes of a DOM element inside the shadow root.
+func (s *ShadowBrowser) GetAttributes(xpath string) (map[string]string, error) {
+	attrs := make(map[string]string)
+	err := s.run(xpath, func(ctx context.Context, ids []cdp.NodeID) error {
+		return chromedp.Attributes(ids, &attrs, chromedp.ByNodeID).Do(ctx)
+	})
+	return attrs, err
+}
+
+// GetSource returns the HTML of the shadow root.
+func (s *ShadowBrowser) GetSource() (string, error) {
+	var html string
+	err := s.b.EvaluateOnNode(s.hostXPath, `function() { return this.shadowRoot ? this.shadowRoot.innerHTML : ''; }`, &html)
+	return html, err
+}
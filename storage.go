@@ -0,0 +1,50 @@
+package cr
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/storage"
+	"github.com/chromedp/chromedp"
+)
+
+// UsageAndQuota reports storage usage for an origin, in bytes.
+type UsageAndQuota struct {
+	Usage          float64
+	Quota          float64
+	OverrideActive bool
+	UsageBreakdown []*storage.UsageForType
+}
+
+// origin returns the origin of the current page.
+func (b *Browser) origin() (string, error) {
+	var origin string
+	err := b.evaluate(`location.origin`, &origin)
+	return origin, err
+}
+
+// GetStorageQuota returns the storage usage and quota of the current
+// origin, broken down by storage type such as IndexedDB and cache storage.
+func (b *Browser) GetStorageQuota() (*UsageAndQuota, error) {
+	origin, err := b.origin()
+	if err != nil {
+		return nil, err
+	}
+	uq := &UsageAndQuota{}
+	err = chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		var err error
+		uq.Usage, uq.Quota, uq.OverrideActive, uq.UsageBreakdown, err = storage.GetUsageAndQuota(origin).Do(ctx)
+		return err
+	}))
+	return uq, err
+}
+
+// ClearSiteData deletes all data stored by the current origin, including
+// cookies, local storage, IndexedDB and caches, like "Clear site data"
+// in Chrome DevTools.
+func (b *Browser) ClearSiteData() error {
+	origin, err := b.origin()
+	if err != nil {
+		return err
+	}
+	return chromedp.Run(b.ctx, storage.ClearDataForOrigin(origin, storage.TypeAll.String()))
+}
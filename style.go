@@ -0,0 +1,36 @@
+package cr
+
+// GetComputedStyle returns the computed value of the CSS property
+// of the DOM element located by xpath, exactly as the browser reports it.
+func (b *Browser) GetComputedStyle(xpath, property string) (string, error) {
+	var value string
+	err := b.callFunctionOnXPath(xpath, `function(property) {
+		return window.getComputedStyle(this).getPropertyValue(property);
+	}`, &value, property)
+	return value, err
+}
+
+// cssPropertyValueJS returns the computed value of a CSS property with
+// every absolute or font-relative length converted to px.
+var cssPropertyValueJS = `function(property) {
+	var style = window.getComputedStyle(this);
+	var fontSize = parseFloat(style.fontSize);
+	var rootFontSize = parseFloat(window.getComputedStyle(document.documentElement).fontSize);
+	var factors = {
+		px: 1, pt: 4 / 3, pc: 16, in: 96, cm: 96 / 2.54, mm: 96 / 25.4, q: 96 / 101.6,
+		em: fontSize, rem: rootFontSize,
+		vw: window.innerWidth / 100, vh: window.innerHeight / 100
+	};
+	return style.getPropertyValue(property).replace(/(-?[\d.]+)(px|pt|pc|in|cm|mm|q|em|rem|vw|vh)\b/gi, function(_, n, unit) {
+		var px = parseFloat(n) * factors[unit.toLowerCase()];
+		return parseFloat(px.toFixed(4)) + 'px';
+	});
+}`
+
+// GetCSSPropertyValue is like GetComputedStyle but converts lengths to px,
+// making assertions independent of the unit the browser reports.
+func (b *Browser) GetCSSPropertyValue(xpath, property string) (string, error) {
+	var value string
+	err := b.callFunctionOnXPath(xpath, cssPropertyValueJS, &value, property)
+	return value, err
+}
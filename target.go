@@ -0,0 +1,198 @@
+package cr
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/cdproto/target"
+	"github.com/chromedp/chromedp"
+)
+
+// browserExecutor returns a context for issuing browser-level
+// CDP commands, such as creating targets.
+func (b *Browser) browserExecutor() context.Context {
+	c := chromedp.FromContext(b.taskCtx)
+	return cdp.WithExecutor(b.taskCtx, c.Browser)
+}
+
+// GetAllTargets returns every target of the Chrome process, such as
+// pages, iframes, workers and service workers.
+func (b *Browser) GetAllTargets() ([]*target.Info, error) {
+	return target.GetTargets().Do(b.browserExecutor())
+}
+
+// newTab returns a *Browser controlling a tab in the same Chrome process
+// as b; a new tab is opened unless opts select an existing target.
+func (b *Browser) newTab(opts ...chromedp.ContextOption) (*Browser, error) {
+	opts = append(opts, chromedp.WithLogf(b.logger.Errorf))
+	ctx, cancel := chromedp.NewContext(b.taskCtx, opts...)
+	nb := &Browser{
+		taskCtx:   ctx,
+		cancelCtx: cancel,
+		timeout:   b.timeout,
+		logger:    b.logger,
+	}
+	nb.ctx, nb.cancelSession = context.WithCancel(ctx)
+	nb.listen()
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return nil, err
+	}
+	return nb, nil
+}
+
+// StopContext ends the current session: pending actions are cancelled,
+// listeners such as captures are removed and later actions fail until
+// ResetContext is called. Unlike Close it leaves Chrome running.
+func (b *Browser) StopContext() error {
+	if b.cancelSession == nil {
+		return nil
+	}
+	var err error
+	if chromedp.FromContext(b.ctx).Target == chromedp.FromContext(b.taskCtx).Target {
+		// the session shares the tab of the *Browser, which outlives it,
+		// so blank the page to stop its timers, sockets and requests
+		ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+		err = chromedp.Run(ctx, chromedp.Navigate(`about:blank`))
+		cancel()
+	}
+	b.cancelSession()
+	b.cancelSession = nil
+	return err
+}
+
+// resetSession discards the state tied to the current session's tab, whose
+// listeners stop along with its context. Data already captured by network
+// and WebSocket captures remains readable.
+func (b *Browser) resetSession() {
+	b.StopConsoleCapture()
+	b.DisableRequestLogging()
+	if b.trace != nil {
+		b.trace.cancel()
+		b.trace = nil
+	}
+	b.StopNetworkCapture()
+	b.StopWebSocketCapture()
+	b.mu.Lock()
+	b.console = nil
+	b.document = nil
+	b.pageErrors = nil
+	b.alerts = nil
+	b.browserLogs = nil
+	b.mediaFeatures = nil
+	b.deviceMetrics = nil
+	b.focusedFrame = ""
+	// a new tab starts without Fetch interception
+	b.interception = nil
+	b.mu.Unlock()
+}
+
+// ResetContext stops the current session and starts a new one in a fresh
+// tab of the same Chrome process, so sequential test sessions can reuse
+// the process instead of launching a new one each time. Per-session
+// state, such as console capture, tracing, request middleware, HTTP
+// credentials and emulation overrides, is discarded. Options given to
+// NewWithOptions that act on the tab, such as WithViewport and
+// WithAnimationsDisabled, are applied to the new tab again.
+func (b *Browser) ResetContext() error {
+	ctx, cancel := chromedp.NewContext(b.taskCtx, chromedp.WithLogf(b.logger.Errorf))
+	if err := chromedp.Run(ctx); err != nil {
+		cancel()
+		return err
+	}
+	if err := b.StopContext(); err != nil {
+		b.logger.Errorf("Failed to blank the previous session's page: %v", err)
+	}
+	b.resetSession()
+	b.ctx, b.cancelSession = ctx, cancel
+	b.listen()
+	for _, fn := range b.afterLaunch {
+		if err := fn(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// attach returns a *Browser controlling the existing target targetID
+// in the same Chrome process as b.
+func (b *Browser) attach(targetID target.ID) (*Browser, error) {
+	return b.newTab(chromedp.WithTargetID(targetID))
+}
+
+// AttachToTarget returns a *Browser controlling an existing target of the
+// Chrome process, such as a tab opened by the page or listed by
+// GetAllTargets. Closing the returned *Browser closes the target.
+func (b *Browser) AttachToTarget(targetID target.ID) (*Browser, error) {
+	return b.attach(targetID)
+}
+
+// BrowserContext is a tab in an isolated browser context. Multiple
+// contexts share a Chrome process but not cookies, storage or cache.
+type BrowserContext struct {
+	*Browser
+	ID cdp.BrowserContextID
+
+	parent   *Browser
+	targetID target.ID
+	closeTab context.CancelFunc
+}
+
+// NewBrowserContext creates a new isolated browser context in the
+// Chrome process controlled by b and opens a tab in it.
+func (b *Browser) NewBrowserContext() (*BrowserContext, error) {
+	ctx := b.browserExecutor()
+	id, err := target.CreateBrowserContext().Do(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bc := &BrowserContext{ID: id, parent: b}
+	bc.targetID, err = target.CreateTarget(`about:blank`).
+		WithBrowserContextID(id).
+		Do(ctx)
+	if err == nil {
+		bc.Browser, err = b.attach(bc.targetID)
+	}
+	if err != nil {
+		if err := target.DisposeBrowserContext(id).Do(ctx); err != nil {
+			b.logger.Errorf("Failed to dispose browser context %s: %s", id, err)
+		}
+		return nil, err
+	}
+	bc.closeTab = bc.Browser.cancelCtx
+	return bc, nil
+}
+
+// Dispose closes the context's tab and disposes of the browser
+// context along with all of its data.
+func (bc *BrowserContext) Dispose() error {
+	ctx := bc.parent.browserExecutor()
+	if err := target.CloseTarget(bc.targetID).Do(ctx); err != nil {
+		bc.logger.Errorf("Failed to close target %s: %s", bc.targetID, err)
+	}
+	bc.closeTab()
+	return target.DisposeBrowserContext(bc.ID).Do(ctx)
+}
+
+// NewIncognito opens a tab in a new incognito browser context of the same
+// Chrome process. The returned *Browser shares no cookies, storage or cache
+// with b; closing it closes the tab and disposes of its browser context.
+func (b *Browser) NewIncognito() (*Browser, error) {
+	bc, err := b.NewBrowserContext()
+	if err != nil {
+		return nil, err
+	}
+	bc.Browser.cancelCtx = func() {
+		if err := bc.Dispose(); err != nil {
+			b.logger.Errorf("Failed to dispose incognito browser context: %s", err)
+		}
+	}
+	return bc.Browser, nil
+}
+
+// Clone opens a new tab in the same Chrome process as b and returns a
+// *Browser controlling it. The clone shares cookies and storage with b
+// but navigates independently, allowing tabs to be driven in parallel.
+func (b *Browser) Clone() (*Browser, error) {
+	return b.newTab()
+}
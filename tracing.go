@@ -0,0 +1,86 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/chromedp/cdproto/tracing"
+	"github.com/chromedp/chromedp"
+)
+
+// DefaultTraceCategories are recorded by StartTracing when no
+// categories are given.
+var DefaultTraceCategories = []string{`devtools.timeline`, `v8`}
+
+// ErrTracingNotStarted is returned by StopTracing when
+// StartTracing has not been called.
+var ErrTracingNotStarted = errors.New("tracing has not been started")
+
+type traceSession struct {
+	mu     sync.Mutex
+	events []json.RawMessage
+	done   chan struct{}
+	cancel context.CancelFunc
+}
+
+// StartTracing begins recording a Chrome DevTools trace of the given
+// categories, or DefaultTraceCategories if none are given.
+func (b *Browser) StartTracing(categories ...string) error {
+	if len(categories) == 0 {
+		categories = DefaultTraceCategories
+	}
+	ctx, cancel := context.WithCancel(b.ctx)
+	ts := &traceSession{
+		events: []json.RawMessage{},
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		switch e := ev.(type) {
+		case *tracing.EventDataCollected:
+			ts.mu.Lock()
+			for _, v := range e.Value {
+				ts.events = append(ts.events, json.RawMessage(v))
+			}
+			ts.mu.Unlock()
+		case *tracing.EventTracingComplete:
+			close(ts.done)
+			cancel()
+		}
+	})
+	err := chromedp.Run(b.ctx, tracing.Start().
+		WithTransferMode(tracing.TransferModeReportEvents).
+		WithTraceConfig(&tracing.TraceConfig{IncludedCategories: categories}))
+	if err != nil {
+		cancel()
+		return err
+	}
+	b.trace = ts
+	return nil
+}
+
+// StopTracing ends the trace started by StartTracing and returns it
+// as JSON that can be loaded into chrome://tracing.
+func (b *Browser) StopTracing() ([]byte, error) {
+	ts := b.trace
+	if ts == nil {
+		return nil, ErrTracingNotStarted
+	}
+	b.trace = nil
+	if err := chromedp.Run(b.ctx, tracing.End()); err != nil {
+		ts.cancel()
+		return nil, err
+	}
+	select {
+	case <-ts.done:
+	case <-b.ctx.Done():
+		return nil, b.ctx.Err()
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return json.Marshal(struct {
+		TraceEvents []json.RawMessage `json:"traceEvents"`
+	}{ts.events})
+}
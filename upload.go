@@ -0,0 +1,43 @@
+package cr
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/chromedp/chromedp"
+)
+
+// UploadFile sets the files selected by the <input type="file">
+// element located by xpath.
+func (b *Browser) UploadFile(xpath string, files ...string) error {
+	return chromedp.Run(b.ctx, chromedp.SetUploadFiles(xpath, files))
+}
+
+// UploadFileFromReader uploads the content of r as a file called name
+// through the <input type="file"> element located by xpath. The content
+// is staged in a temporary file, which the browser reads when the form is
+// submitted, so it is only removed when the *Browser is closed.
+func (b *Browser) UploadFileFromReader(xpath string, name string, r io.Reader) error {
+	dir, err := ioutil.TempDir("", "cr-upload-")
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.tempDirs = append(b.tempDirs, dir)
+	b.mu.Unlock()
+	path := filepath.Join(dir, filepath.Base(name))
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return b.UploadFile(xpath, path)
+}
@@ -0,0 +1,40 @@
+package cr
+
+import (
+	"strings"
+
+	"github.com/chromedp/cdproto/browser"
+)
+
+// ChromeVersion describes the running browser, as reported by
+// the Browser.getVersion DevTools command.
+type ChromeVersion struct {
+	ProtocolVersion string
+	Product         string
+	Revision        string
+	UserAgent       string
+	JSVersion       string
+}
+
+// GetChromeVersion returns version information about the running browser,
+// e.g. a Product of "HeadlessChrome/91.0.4472.114".
+func (b *Browser) GetChromeVersion() (*ChromeVersion, error) {
+	v := &ChromeVersion{}
+	var err error
+	v.ProtocolVersion, v.Product, v.Revision, v.UserAgent, v.JSVersion, err = browser.GetVersion().Do(b.browserExecutor())
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// IsHeadless reports whether Chrome is running headless. It relies on
+// the product reported by the browser, which is unaffected by user agent
+// overrides and by flags hiding automation from the page.
+func (b *Browser) IsHeadless() (bool, error) {
+	v, err := b.GetChromeVersion()
+	if err != nil {
+		return false, err
+	}
+	return strings.HasPrefix(v.Product, `HeadlessChrome/`), nil
+}
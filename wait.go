@@ -0,0 +1,171 @@
+package cr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+// WaitForFunction evaluates the JavaScript expression js every interval
+// until it returns a truthy value. It returns chromedp.ErrPollingTimeout
+// if the expression is still falsy after timeout. The expression always
+// runs in the main frame, regardless of FocusFrame.
+func (b *Browser) WaitForFunction(js string, interval, timeout time.Duration) error {
+	return chromedp.Run(b.ctx, chromedp.Poll(js, nil,
+		chromedp.WithPollingInterval(interval),
+		chromedp.WithPollingTimeout(timeout),
+	))
+}
+
+// WaitForNavigation runs action, such as a click on a link, and blocks
+// until the navigation it triggers has finished loading or the browser
+// timeout elapses.
+func (b *Browser) WaitForNavigation(action chromedp.Action) error {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	loaded := make(chan struct{})
+	var once sync.Once
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		if _, ok := ev.(*page.EventLoadEventFired); ok {
+			once.Do(func() { close(loaded) })
+		}
+	})
+	if err := chromedp.Run(b.ctx, action); err != nil {
+		return err
+	}
+	select {
+	case <-loaded:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WaitForSelector waits until the element matched by selector is visible.
+// The selector is treated as a CSS query unless another chromedp
+// selector type, such as chromedp.BySearch, is passed in by.
+func (b *Browser) WaitForSelector(selector string, by ...func(s *chromedp.Selector)) error {
+	byType := chromedp.ByQuery
+	if len(by) > 0 {
+		byType = by[0]
+	}
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	return chromedp.Run(ctx, chromedp.WaitVisible(selector, byType))
+}
+
+var waitForAllImagesJS = `
+	Promise.all(Array.from(document.images).map(function(img) {
+		return img.complete ? Promise.resolve() : new Promise(function(resolve) {
+			img.addEventListener('load', resolve);
+			img.addEventListener('error', resolve);
+		});
+	}));
+	`
+
+// WaitForAllImages blocks until every image on the page has finished
+// loading, successfully or not, or the browser timeout elapses.
+func (b *Browser) WaitForAllImages() error {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	return b.evaluateContext(ctx, waitForAllImagesJS, nil, awaitPromise)
+}
+
+var waitForDOMStableJS = `
+	new Promise(function(resolve) {
+		var timer = setTimeout(done, %d);
+		var observer = new MutationObserver(function() {
+			clearTimeout(timer);
+			timer = setTimeout(done, %d);
+		});
+		function done() {
+			observer.disconnect();
+			resolve();
+		}
+		observer.observe(document, {subtree: true, childList: true, attributes: true, characterData: true});
+	});
+	`
+
+// WaitForDOMStable blocks until the DOM has gone quietPeriod without any
+// mutation, or the browser timeout elapses. This suits pages rendered
+// on the client, which may keep changing after the network is idle.
+func (b *Browser) WaitForDOMStable(quietPeriod time.Duration) error {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	ms := quietPeriod.Milliseconds()
+	return b.evaluateContext(ctx, fmt.Sprintf(waitForDOMStableJS, ms, ms), nil, awaitPromise)
+}
+
+// pollInterval is how often the polling Wait* helpers re-check their condition.
+const pollInterval = 100 * time.Millisecond
+
+// pollUntil calls cond every pollInterval until it reports true, returns
+// an error, or the browser timeout elapses.
+func (b *Browser) pollUntil(cond func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(b.ctx, b.timeout)
+	defer cancel()
+	for {
+		ok, err := cond(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// WaitForElementText waits until the text of the DOM element located by
+// xpath equals expected or, when exact is false, contains it.
+func (b *Browser) WaitForElementText(xpath, expected string, exact bool) error {
+	return b.pollUntil(func(ctx context.Context) (bool, error) {
+		text, err := b.getText(ctx, xpath)
+		if err != nil {
+			return false, err
+		}
+		if exact {
+			return text == expected, nil
+		}
+		return strings.Contains(text, expected), nil
+	})
+}
+
+// WaitForTextChange reads the text of the DOM element located by xpath
+// and waits until it differs, e.g. a counter or search results updating.
+func (b *Browser) WaitForTextChange(xpath string) error {
+	// the initial read shares the browser timeout with the polling
+	var initial *string
+	return b.pollUntil(func(ctx context.Context) (bool, error) {
+		text, err := b.getText(ctx, xpath)
+		if err != nil {
+			return false, err
+		}
+		if initial == nil {
+			initial = &text
+			return false, nil
+		}
+		return text != *initial, nil
+	})
+}
+
+// WaitForAttributeValue waits until the named attribute of the DOM element
+// located by xpath equals expected, e.g. aria-expanded becoming "true".
+func (b *Browser) WaitForAttributeValue(xpath, attrName, expected string) error {
+	return b.pollUntil(func(ctx context.Context) (bool, error) {
+		value, _, err := b.getNodeAttribute(ctx, xpath, attrName)
+		if err != nil {
+			return false, err
+		}
+		return value == expected, nil
+	})
+}
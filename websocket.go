@@ -0,0 +1,84 @@
+package cr
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// ErrWebSocketCaptureNotStarted is returned when WebSocket messages
+// are requested before StartWebSocketCapture.
+var ErrWebSocketCaptureNotStarted = errors.New("websocket capture has not been started")
+
+// WebSocketMessage is a frame sent or received over a WebSocket.
+// Binary payloads are base64 encoded.
+type WebSocketMessage struct {
+	RequestID network.RequestID
+	Sent      bool
+	Payload   string
+	Timestamp time.Time
+}
+
+type webSocketCapture struct {
+	mu       sync.Mutex
+	messages []WebSocketMessage
+	cancel   context.CancelFunc
+}
+
+func (c *webSocketCapture) handle(ev interface{}) {
+	var msg WebSocketMessage
+	switch e := ev.(type) {
+	case *network.EventWebSocketFrameSent:
+		msg = WebSocketMessage{RequestID: e.RequestID, Sent: true, Payload: e.Response.PayloadData}
+	case *network.EventWebSocketFrameReceived:
+		msg = WebSocketMessage{RequestID: e.RequestID, Payload: e.Response.PayloadData}
+	default:
+		return
+	}
+	// the event timestamps are monotonic, not wall clock
+	msg.Timestamp = time.Now()
+	c.mu.Lock()
+	c.messages = append(c.messages, msg)
+	c.mu.Unlock()
+}
+
+// StartWebSocketCapture begins recording the WebSocket frames sent and
+// received by the page, discarding anything recorded by a previous capture.
+func (b *Browser) StartWebSocketCapture() error {
+	b.StopWebSocketCapture()
+	ctx, cancel := context.WithCancel(b.ctx)
+	c := &webSocketCapture{cancel: cancel}
+	chromedp.ListenTarget(ctx, c.handle)
+	if err := chromedp.Run(b.ctx, network.Enable()); err != nil {
+		cancel()
+		return err
+	}
+	b.webSocket = c
+	return nil
+}
+
+// StopWebSocketCapture stops recording WebSocket frames. Messages recorded
+// so far remain available through GetWebSocketMessages.
+func (b *Browser) StopWebSocketCapture() {
+	if b.webSocket != nil {
+		b.webSocket.cancel()
+	}
+}
+
+// GetWebSocketMessages returns the frames recorded by the current or
+// most recent WebSocket capture, in the order they were seen.
+func (b *Browser) GetWebSocketMessages() ([]WebSocketMessage, error) {
+	c := b.webSocket
+	if c == nil {
+		return nil, ErrWebSocketCaptureNotStarted
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	messages := make([]WebSocketMessage, len(c.messages))
+	copy(messages, c.messages)
+	return messages, nil
+}
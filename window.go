@@ -0,0 +1,105 @@
+package cr
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/chromedp/cdproto/browser"
+	"github.com/chromedp/cdproto/cdp"
+	"github.com/chromedp/chromedp"
+	"github.com/mailru/easyjson/jwriter"
+)
+
+// windowBounds mirrors browser.Bounds, whose fields are all omitempty,
+// with pointers so that zero coordinates and sizes are sent as such.
+type windowBounds struct {
+	Left        *int64              `json:"left,omitempty"`
+	Top         *int64              `json:"top,omitempty"`
+	Width       *int64              `json:"width,omitempty"`
+	Height      *int64              `json:"height,omitempty"`
+	WindowState browser.WindowState `json:"windowState,omitempty"`
+}
+
+type setWindowBoundsParams struct {
+	WindowID browser.WindowID `json:"windowId"`
+	Bounds   *windowBounds    `json:"bounds"`
+}
+
+// MarshalEasyJSON lets the params be sent with cdp.Execute.
+func (p *setWindowBoundsParams) MarshalEasyJSON(w *jwriter.Writer) {
+	w.Raw(json.Marshal(*p))
+}
+
+// setWindowBounds applies bounds to the window hosting the current target.
+func (b *Browser) setWindowBounds(bounds *windowBounds) error {
+	return chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		windowID, current, err := browser.GetWindowForTarget().Do(ctx)
+		if err != nil {
+			return err
+		}
+		resize := bounds.Left != nil || bounds.Top != nil || bounds.Width != nil || bounds.Height != nil
+		if resize && current.WindowState != browser.WindowStateNormal {
+			// Chrome refuses to move or resize a maximized,
+			// minimized or fullscreen window
+			err := browser.SetWindowBounds(windowID, &browser.Bounds{WindowState: browser.WindowStateNormal}).Do(ctx)
+			if err != nil {
+				return err
+			}
+		}
+		params := &setWindowBoundsParams{WindowID: windowID, Bounds: bounds}
+		return cdp.Execute(ctx, browser.CommandSetWindowBounds, params, nil)
+	}))
+}
+
+// SetWindowSize resizes the outer browser window. Unlike a viewport
+// override this changes window.outerWidth and window.outerHeight.
+func (b *Browser) SetWindowSize(width, height int) error {
+	w, h := int64(width), int64(height)
+	return b.setWindowBounds(&windowBounds{Width: &w, Height: &h})
+}
+
+// MaximizeWindow maximizes the browser window.
+func (b *Browser) MaximizeWindow() error {
+	return b.setWindowBounds(&windowBounds{WindowState: browser.WindowStateMaximized})
+}
+
+// MinimizeWindow minimizes the browser window.
+func (b *Browser) MinimizeWindow() error {
+	return b.setWindowBounds(&windowBounds{WindowState: browser.WindowStateMinimized})
+}
+
+// FullscreenWindow switches the browser window to fullscreen,
+// which is useful for kiosk-mode testing.
+func (b *Browser) FullscreenWindow() error {
+	return b.setWindowBounds(&windowBounds{WindowState: browser.WindowStateFullscreen})
+}
+
+// GetWindowPosition returns the screen coordinates of the
+// top-left corner of the browser window.
+func (b *Browser) GetWindowPosition() (x, y int, err error) {
+	err = chromedp.Run(b.ctx, chromedp.ActionFunc(func(ctx context.Context) error {
+		windowID, _, err := browser.GetWindowForTarget().Do(ctx)
+		if err != nil {
+			return err
+		}
+		bounds, err := browser.GetWindowBounds(windowID).Do(ctx)
+		if err != nil {
+			return err
+		}
+		x, y = int(bounds.Left), int(bounds.Top)
+		return nil
+	}))
+	return
+}
+
+// SetWindowPosition moves the browser window to the given screen coordinates.
+func (b *Browser) SetWindowPosition(x, y int) error {
+	left, top := int64(x), int64(y)
+	return b.setWindowBounds(&windowBounds{Left: &left, Top: &top})
+}
+
+// SetWindowTitle sets document.title, which is handy for labelling
+// the current step in screen recordings and screencasts.
+func (b *Browser) SetWindowTitle(title string) error {
+	return b.evaluate(`document.title = `+jsString(title), nil)
+}